@@ -0,0 +1,240 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"net/url"
+	"strings"
+)
+
+// isNotFoundError reports whether an error returned by the muleb2b client is the API's answer to a request for an
+// object that doesn't exist
+func isNotFoundError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "error code (404)") || strings.Contains(strings.ToLower(err.Error()), "not found"))
+}
+
+// doPartnerApiRequest sends a request to a Partner Manager API operation that the muleb2b client does not provide.
+// path is relative to the environment, i.e. organizations/{orgId}/environments/{envId}/. The client does not expose
+// its organization, so it is taken from the environment.
+func doPartnerApiRequest(client *muleb2b.Client, envId, method, path string, body, v interface{}) error {
+	env, err := client.GetEnvironmentById(envId)
+	if err != nil {
+		return err
+	} else if env == nil || env.OrgId == nil {
+		return fmt.Errorf("environment (%s) not found", envId)
+	}
+
+	rel := &url.URL{Path: fmt.Sprintf("organizations/%s/environments/%s/%s", *env.OrgId, envId, path)}
+	u := client.PartnerBaseURL.ResolveReference(rel)
+
+	req, err := client.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(req, v)
+	return err
+}
+
+func updatePartnerIdentifier(client *muleb2b.Client, envId, partnerId string, identifier *muleb2b.Identifier) error {
+	if identifier.Id == nil || *identifier.Id == "" {
+		return fmt.Errorf("identifier (%s) has no ID", identifier.String())
+	}
+	return doPartnerApiRequest(client, envId, "PUT", fmt.Sprintf("partners/%s/identifiers/%s", partnerId, *identifier.Id), identifier, nil)
+}
+
+// Endpoint type IDs known to the muleb2b client, other endpoint types are looked up by name
+var endpointTypeIds = map[string]string{
+	"sftp": "3bcc65e5-040b-47eb-8fc7-27c89225f1bc",
+	"http": "aa1fd35b-50af-47fe-91bb-48a7ed4ab685",
+}
+
+// apiEndpoint is a muleb2b.Endpoint with the configuration settings the muleb2b client does not model. An update
+// without a configuration keeps the current one, see TestAccMuleB2bEndpoint_updateWithoutConfig
+type apiEndpoint struct {
+	muleb2b.Endpoint
+	Config      *apiEndpointConfig `json:"config,omitempty"`
+	RetryPolicy *apiRetryPolicy    `json:"retryPolicy,omitempty"`
+}
+
+// apiRetryPolicy controls how often a failed delivery is retried before it is sent to the dead letter endpoint
+type apiRetryPolicy struct {
+	MaxAttempts          *int    `json:"maxAttempts,omitempty"`
+	BackoffInterval      *int    `json:"backoffInterval,omitempty"`
+	RetryableStatusCodes []int   `json:"retryableStatusCodes,omitempty"`
+	DeadLetterEndpointId *string `json:"deadLetterEndpointId,omitempty"`
+}
+
+type apiEndpointConfig struct {
+	muleb2b.EndpointConfig
+
+	AuthMode   *apiAuthMode   `json:"authMode,omitempty"`
+	TlsContext *apiTlsContext `json:"tlsContext,omitempty"`
+	Proxy      *apiProxy      `json:"proxy,omitempty"`
+
+	// SecretVersion is only kept in the state, changing it sends the secrets to the API again
+	SecretVersion int `json:"-"`
+	// SecretSources holds the <secret>_file and <secret>_env attributes, the secrets are resolved right before they are sent
+	SecretSources map[string]string `json:"-"`
+	// Url is the url attribute of the http_config or sftp_config block the server fields were parsed from
+	Url *string `json:"-"`
+
+	// HTTP
+	Method      *string           `json:"method,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ContentType *string           `json:"contentType,omitempty"`
+	QueryParams map[string]string `json:"queryParams,omitempty"`
+
+	// SFTP
+	PrivateKey         *string `json:"privateKey,omitempty"`
+	Passphrase         *string `json:"passphrase,omitempty"`
+	HostKeyFingerprint *string `json:"hostKeyFingerprint,omitempty"`
+	KnownHosts         *string `json:"knownHosts,omitempty"`
+	FileNamePattern    *string `json:"fileNamePattern,omitempty"`
+	DeleteAfterRead    *bool   `json:"deleteAfterRead,omitempty"`
+	WorkingDirectory   *string `json:"workingDirectory,omitempty"`
+	OutputFileName     *string `json:"outputFileName,omitempty"`
+	WriteMode          *string `json:"writeMode,omitempty"`
+	FileExistsAction   *string `json:"fileExistsAction,omitempty"`
+
+	// FTP
+	PassiveMode  *bool   `json:"passiveMode,omitempty"`
+	TransferMode *string `json:"transferMode,omitempty"`
+	FtpsMode     *string `json:"ftpsMode,omitempty"`
+
+	// AS2
+	As2From                 *string `json:"as2From,omitempty"`
+	As2To                   *string `json:"as2To,omitempty"`
+	SigningAlgorithm        *string `json:"signingAlgorithm,omitempty"`
+	EncryptionAlgorithm     *string `json:"encryptionAlgorithm,omitempty"`
+	CompressionEnabled      *bool   `json:"compressionEnabled,omitempty"`
+	MdnMode                 *string `json:"mdnMode,omitempty"`
+	MdnSigned               *bool   `json:"mdnSigned,omitempty"`
+	MicAlgorithm            *string `json:"micAlgorithm,omitempty"`
+	AsyncMdnUrl             *string `json:"asyncMdnUrl,omitempty"`
+	SigningCertificateId    *string `json:"signingCertificateId,omitempty"`
+	EncryptionCertificateId *string `json:"encryptionCertificateId,omitempty"`
+
+	// Anypoint MQ
+	Destination       *string           `json:"destination,omitempty"`
+	DestinationType   *string           `json:"destinationType,omitempty"`
+	Region            *string           `json:"region,omitempty"`
+	MessageProperties map[string]string `json:"messageProperties,omitempty"`
+
+	// Object storage
+	Bucket          *string `json:"bucket,omitempty"`
+	Container       *string `json:"container,omitempty"`
+	Prefix          *string `json:"prefix,omitempty"`
+	ArchivePrefix   *string `json:"archivePrefix,omitempty"`
+	ServiceEndpoint *string `json:"serviceEndpoint,omitempty"`
+	AccessKeyId     *string `json:"accessKeyId,omitempty"`
+	SecretAccessKey *string `json:"secretAccessKey,omitempty"`
+	AccountName     *string `json:"accountName,omitempty"`
+	AccountKey      *string `json:"accountKey,omitempty"`
+}
+
+// apiAuthMode is a muleb2b.AuthMode with the OAuth token request settings
+type apiAuthMode struct {
+	muleb2b.AuthMode
+	Scope           *string           `json:"scope,omitempty"`
+	Audience        *string           `json:"audience,omitempty"`
+	Resource        *string           `json:"resource,omitempty"`
+	ClientAuthStyle *string           `json:"clientAuthStyle,omitempty"`
+	TokenParams     map[string]string `json:"tokenParams,omitempty"`
+}
+
+// apiProxy is the egress proxy an endpoint connects to its partner through
+type apiProxy struct {
+	Type          *string  `json:"type,omitempty"`
+	Host          *string  `json:"host"`
+	Port          *int     `json:"port"`
+	Username      *string  `json:"username,omitempty"`
+	Password      *string  `json:"password,omitempty"`
+	NonProxyHosts []string `json:"nonProxyHosts,omitempty"`
+
+	// SecretVersion and SecretSources are only kept in the state, like the ones of the endpoint configuration
+	SecretVersion int               `json:"-"`
+	SecretSources map[string]string `json:"-"`
+}
+
+// apiTlsContext is a muleb2b.TlsContext with trust store, key store and protocol settings
+type apiTlsContext struct {
+	muleb2b.TlsContext
+	TrustStore   *apiTrustStore `json:"trustStore,omitempty"`
+	KeyStore     *apiKeyStore   `json:"keyStore,omitempty"`
+	TlsVersions  []string       `json:"enabledProtocols,omitempty"`
+	CipherSuites []string       `json:"enabledCipherSuites,omitempty"`
+}
+
+type apiTrustStore struct {
+	Pem            *string  `json:"pem,omitempty"`
+	CertificateIds []string `json:"certificateIds,omitempty"`
+}
+
+type apiKeyStore struct {
+	CertificatePem *string `json:"certificatePem,omitempty"`
+	PrivateKeyPem  *string `json:"privateKeyPem,omitempty"`
+	CertificateId  *string `json:"certificateId,omitempty"`
+}
+
+type endpointType struct {
+	Id   *string `json:"id"`
+	Name *string `json:"name"`
+}
+
+func findEndpointTypeId(client *muleb2b.Client, envId, name string) (string, error) {
+	if id, ok := endpointTypeIds[name]; ok {
+		return id, nil
+	}
+
+	var types []endpointType
+	err := doPartnerApiRequest(client, envId, "GET", "endpointTypes", nil, &types)
+	if err != nil {
+		return "", err
+	}
+
+	for _, t := range types {
+		if t.Id != nil && t.Name != nil && strings.EqualFold(*t.Name, name) {
+			return *t.Id, nil
+		}
+	}
+	return "", fmt.Errorf("endpoint type (%s) not found", name)
+}
+
+func getEndpoint(client *muleb2b.Client, envId, id string) (*apiEndpoint, error) {
+	var endpoint apiEndpoint
+	err := doPartnerApiRequest(client, envId, "GET", fmt.Sprintf("endpoints/%s", id), nil, &endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func createEndpoint(client *muleb2b.Client, envId string, endpoint *apiEndpoint) (*string, error) {
+	if endpoint.EndpointTypeID == nil || *endpoint.EndpointTypeID == "" {
+		typeId, err := findEndpointTypeId(client, envId, *endpoint.EndpointType)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.EndpointTypeID = muleb2b.String(typeId)
+	}
+
+	var id string
+	err := doPartnerApiRequest(client, envId, "POST", "endpoints", endpoint, &id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func updateEndpoint(client *muleb2b.Client, envId string, endpoint *apiEndpoint) error {
+	if endpoint.EndpointTypeID == nil || *endpoint.EndpointTypeID == "" {
+		typeId, err := findEndpointTypeId(client, envId, *endpoint.EndpointType)
+		if err != nil {
+			return err
+		}
+		endpoint.EndpointTypeID = muleb2b.String(typeId)
+	}
+
+	return doPartnerApiRequest(client, envId, "PUT", fmt.Sprintf("endpoints/%s", *endpoint.ID), endpoint, nil)
+}
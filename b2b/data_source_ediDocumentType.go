@@ -2,7 +2,6 @@ package b2b
 
 import (
 	"fmt"
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -45,7 +44,7 @@ func dataSourceEdiDocumentType() *schema.Resource {
 }
 
 func dataSourceEdiDocumentTypeRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -2,7 +2,6 @@ package b2b
 
 import (
 	"fmt"
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
@@ -82,7 +81,7 @@ func testDataSourceEdiDocumentType_InitialCheck() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		_, err := client.GetDocumentById(partner, id)
 		if err != nil {
 			return err
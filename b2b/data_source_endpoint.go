@@ -0,0 +1,216 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"github.com/hashicorp/terraform/helper/schema"
+	"strings"
+)
+
+func dataSourceEndpoint() *schema.Resource {
+	endpoint := resourceEndpoint()
+
+	return &schema.Resource{
+		Read: dataSourceEndpointRead,
+		Schema: map[string]*schema.Schema{
+			"environment_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the environment to lookup the Endpoint in",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Exact name of the endpoint",
+			},
+			"partner_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the partner that owns the endpoint",
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateRole,
+				Description:  "The role the endpoint plays: send, receive, receive_ack, storage_api",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateType,
+				Description:  "The type of endpoint, http, sftp, as2, ftp, anypoint_mq, s3, or azure_blob",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of the endpoint",
+			},
+			"partner_certificate_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the certificate used when a certificate is needed",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the endpoint, built from its configuration",
+			},
+			"retry_policy":       computedEndpointConfigSchema(endpoint.Schema["retry_policy"]),
+			"http_config":        computedEndpointConfigSchema(endpoint.Schema["http_config"]),
+			"sftp_config":        computedEndpointConfigSchema(endpoint.Schema["sftp_config"]),
+			"ftp_config":         computedEndpointConfigSchema(endpoint.Schema["ftp_config"]),
+			"as2_config":         computedEndpointConfigSchema(endpoint.Schema["as2_config"]),
+			"anypoint_mq_config": computedEndpointConfigSchema(endpoint.Schema["anypoint_mq_config"]),
+			"s3_config":          computedEndpointConfigSchema(endpoint.Schema["s3_config"]),
+			"azure_blob_config":  computedEndpointConfigSchema(endpoint.Schema["azure_blob_config"]),
+		},
+	}
+}
+
+// computedEndpointConfigSchema turns a configuration block of the endpoint resource into a computed attribute.
+// Secrets and the attributes they are read from are left out, the API doesn't return them
+func computedEndpointConfigSchema(s *schema.Schema) *schema.Schema {
+	out := &schema.Schema{
+		Type:        s.Type,
+		Computed:    true,
+		Description: s.Description,
+	}
+
+	switch elem := s.Elem.(type) {
+	case *schema.Resource:
+		attributes := make(map[string]*schema.Schema)
+		for k, v := range elem.Schema {
+			if endpointSecretFields[k] || strings.HasSuffix(k, "_file") || strings.HasSuffix(k, "_env") {
+				continue
+			}
+			attributes[k] = computedEndpointConfigSchema(v)
+		}
+		out.Elem = &schema.Resource{Schema: attributes}
+	case *schema.Schema:
+		out.Elem = &schema.Schema{Type: elem.Type}
+	}
+	return out
+}
+
+func dataSourceEndpointRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	envId := d.Get("environment_id").(string)
+	client.SetEnvironment(envId)
+
+	endpoints, err := client.ListEndpoints()
+	if err != nil {
+		return err
+	}
+
+	name, nameOk := d.GetOk("name")
+	partnerId, partnerOk := d.GetOk("partner_id")
+	role, roleOk := d.GetOk("role")
+	endType, typeOk := d.GetOk("type")
+	if !nameOk && !partnerOk && !roleOk && !typeOk {
+		return fmt.Errorf("no endpoint name, partner_id, role, or type specified")
+	}
+
+	var matches []muleb2b.Endpoint
+	if endpoints != nil {
+		for _, endpoint := range *endpoints {
+			if endpoint.ID == nil {
+				continue
+			}
+			if nameOk && (endpoint.Name == nil || *endpoint.Name != name.(string)) {
+				continue
+			}
+			if partnerOk && (endpoint.PartnerID == nil || *endpoint.PartnerID != partnerId.(string)) {
+				continue
+			}
+			if roleOk && (endpoint.EndpointRole == nil || !strings.EqualFold(*endpoint.EndpointRole, role.(string))) {
+				continue
+			}
+			if typeOk && (endpoint.EndpointType == nil || !strings.EqualFold(*endpoint.EndpointType, endType.(string))) {
+				continue
+			}
+			matches = append(matches, endpoint)
+		}
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no endpoint found in environment (%s) matching %s", envId, describeEndpointFilter(d))
+	}
+	if len(matches) > 1 {
+		var ids []string
+		for _, endpoint := range matches {
+			ids = append(ids, *endpoint.ID)
+		}
+		return fmt.Errorf("%d endpoints found in environment (%s) matching %s, use name, partner_id, role, and type to select one: %s",
+			len(matches), envId, describeEndpointFilter(d), strings.Join(ids, ", "))
+	}
+
+	// The list only returns a summary, so retrieve the full endpoint
+	endpoint, err := getEndpoint(client, envId, *matches[0].ID)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(*matches[0].ID)
+	if endpoint.Name != nil {
+		d.Set("name", *endpoint.Name)
+	}
+	if endpoint.PartnerID != nil {
+		d.Set("partner_id", *endpoint.PartnerID)
+	}
+	if endpoint.EndpointRole != nil {
+		d.Set("role", strings.ToLower(*endpoint.EndpointRole))
+	}
+	if endpoint.EndpointType != nil {
+		d.Set("type", *endpoint.EndpointType)
+	}
+	if endpoint.Description != nil {
+		d.Set("description", *endpoint.Description)
+	}
+	if endpoint.PartnerCertificateID != nil {
+		d.Set("partner_certificate_id", *endpoint.PartnerCertificateID)
+	}
+
+	if err := d.Set("retry_policy", flattenRetryPolicy(endpoint.RetryPolicy)); err != nil {
+		return err
+	}
+
+	if endpoint.Config == nil || endpoint.EndpointType == nil {
+		return nil
+	}
+	d.Set("url", endpointUrl(endpoint))
+
+	// Only the non-secret configuration is exposed
+	omitSecrets(endpoint.Config)
+	switch *endpoint.EndpointType {
+	case "http":
+		err = d.Set("http_config", flattenHttpConfig(endpoint.Config, nil))
+	case "sftp":
+		err = d.Set("sftp_config", flattenSftpConfig(endpoint.Config, nil))
+	case "ftp":
+		err = d.Set("ftp_config", flattenFtpConfig(endpoint.Config, nil))
+	case "as2":
+		err = d.Set("as2_config", flattenAs2Config(endpoint.Config))
+	case "anypoint_mq":
+		err = d.Set("anypoint_mq_config", flattenAnypointMqConfig(endpoint.Config, nil))
+	case "s3":
+		err = d.Set("s3_config", flattenS3Config(endpoint.Config, nil))
+	case "azure_blob":
+		err = d.Set("azure_blob_config", flattenAzureBlobConfig(endpoint.Config, nil))
+	}
+	return err
+}
+
+func describeEndpointFilter(d *schema.ResourceData) string {
+	var filters []string
+	for _, k := range []string{"name", "partner_id", "role", "type"} {
+		if v, ok := d.GetOk(k); ok {
+			filters = append(filters, fmt.Sprintf("%s (%s)", k, v.(string)))
+		}
+	}
+	return strings.Join(filters, ", ")
+}
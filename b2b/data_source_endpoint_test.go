@@ -0,0 +1,103 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestAccMuleB2bEndpointDS(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceEndpoint_Config(envName, name, fmt.Sprintf(`name = "%s"
+  partner_id = muleb2b_partner.test.id`, name)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.muleb2b_endpoint.test", "id", "muleb2b_endpoint.test", "id"),
+					resource.TestCheckResourceAttr("data.muleb2b_endpoint.test", "role", "send"),
+					resource.TestCheckResourceAttr("data.muleb2b_endpoint.test", "type", "http"),
+					resource.TestCheckResourceAttr("data.muleb2b_endpoint.test", "http_config.#", "1"),
+				),
+			},
+			{
+				Config: testDataSourceEndpoint_Config(envName, name, `partner_id = muleb2b_partner.test.id
+  role = "receive"`),
+				ExpectError: regexp.MustCompile("no endpoint found"),
+			},
+			{
+				Config:      testDataSourceEndpoint_Config(envName, name, `type = "http"`),
+				ExpectError: regexp.MustCompile("endpoints found .* use name, partner_id, role, and type to select one"),
+			},
+		},
+	})
+}
+
+func testDataSourceEndpoint_Config(envName, name, filter string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "http"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  http_config {
+    server_address = "test.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode  {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }
+}
+
+resource "muleb2b_endpoint" "other" {
+  name = "%s-other"
+  role = "send"
+  type = "http"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  http_config {
+    server_address = "other.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode  {
+      type = "none"
+    }
+  }
+}
+
+data "muleb2b_endpoint" "test" {
+  environment_id = data.muleb2b_environment.sbx.id
+  %s
+  depends_on = [muleb2b_endpoint.test, muleb2b_endpoint.other]
+}`, envName, name, name, name, name, filter)
+}
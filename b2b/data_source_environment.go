@@ -2,7 +2,6 @@ package b2b
 
 import (
 	"fmt"
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -20,7 +19,7 @@ func dataSourceEnvironment() *schema.Resource {
 }
 
 func dataSourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	if v, ok := d.GetOk("name"); ok {
 		name := v.(string)
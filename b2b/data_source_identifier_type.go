@@ -40,7 +40,7 @@ func dataSourceIdentifierType() *schema.Resource {
 }
 
 func dataSourceIdentifierTypeRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -13,6 +13,7 @@ func dataSourcePartner() *schema.Resource {
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Description: "Exact name of the partner",
 			},
 			"host": {
@@ -20,38 +21,265 @@ func dataSourcePartner() *schema.Resource {
 				Optional:    true,
 				Description: "True if the host should be retrieved, name will be ignored",
 			},
+			"identifier_type_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the identifier type to lookup the partner by, requires identifier_value",
+			},
+			"identifier_value": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Value of the identifier to lookup the partner by, requires identifier_type_id",
+			},
 			"environment_id": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The ID of the environment to lookup Partner in",
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of the partner",
+			},
+			"website_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the partner's website",
+			},
+			"identifier": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Set of identifiers for the partner",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the identifier",
+						},
+						"identifier_type_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the identifier type",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The identifier value",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the identifier",
+						},
+					},
+				},
+			},
+			"x12_inbound_config": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "X12 Inbound Configuration",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"character_encoding": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"character_set": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"acknowledgements": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"endpoint_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"generate_ta1": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"failure_acknowledgement_type": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"validations": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"fail_when_value_length_outside_allowed_range": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_when_unused_segments_included": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_when_too_many_repeats_of_segment": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_when_segments_out_of_order": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_when_invalid_character_in_value": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_if_value_repeated_too_many_times": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"fail_if_unknown_segments_used": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"control_numbers": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"require_unique_interchange_number": {
+										Type:        schema.TypeBool,
+										Computed:    true,
+										Description: "Require unique interchange control number (ISA13)",
+									},
+									"require_unique_group_number": {
+										Type:        schema.TypeBool,
+										Computed:    true,
+										Description: "Require unique group control number (GS06)",
+									},
+									"require_unique_transaction_set_number": {
+										Type:        schema.TypeBool,
+										Computed:    true,
+										Description: "Require unique transaction set control number (ST02)",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"contact": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Contacts associated with the partner",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the contact",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the contact",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Contact's full name",
+						},
+						"email": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Contact's email address",
+						},
+						"phone": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Contact's phone number",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Contact type: business, technical, or other",
+						},
+					},
+				},
+			},
+			"address": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Address of the partner",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the address",
+						},
+						"address_line_1": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Line 1 of address",
+						},
+						"address_line_2": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Line 2 of address",
+						},
+						"country": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Company's country",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Company's state or province",
+						},
+						"city": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Company's city",
+						},
+						"postal_code": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Company's postal code",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourcePartnerRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string)
 	client.SetEnvironment(envId)
 
-	if v, ok := d.GetOk("host"); ok {
-		if v.(bool) {
-			partner, err := client.GetHostPartner()
-			if err != nil {
-				return err
-			}
-			if partner != nil {
-				d.SetId(*partner.Id)
-				d.Set("name", *partner.Name)
-			}
+	var partner *muleb2b.Partner
+	var err error
+
+	if v, ok := d.GetOk("host"); ok && v.(bool) {
+		partner, err = client.GetHostPartner()
+		if err != nil {
+			return err
 		}
-	}
-	if v, ok := d.GetOk("name"); ok {
-		name := v.(string)
 
-		partner, err := client.GetPartnerByName(name)
+		if partner == nil || partner.Id == nil || *partner.Id == "" {
+			return fmt.Errorf("no host partner found in environment (%s)", envId)
+		}
+	} else if v, ok := d.GetOk("name"); ok {
+		name := v.(string)
 
+		partner, err = client.GetPartnerByName(name)
 		if err != nil {
 			return err
 		}
@@ -59,12 +287,101 @@ func dataSourcePartnerRead(d *schema.ResourceData, meta interface{}) error {
 		if partner == nil || partner.Id == nil || *partner.Id == "" {
 			return fmt.Errorf("no partner found with name (%s)", name)
 		}
+	} else if v, ok := d.GetOk("identifier_type_id"); ok {
+		typeId := v.(string)
+		value, ok := d.GetOk("identifier_value")
+		if !ok {
+			return fmt.Errorf("identifier_value is required when identifier_type_id is specified")
+		}
+
+		partner, err = getPartnerByIdentifier(client, typeId, value.(string))
+		if err != nil {
+			return err
+		}
+
+		if partner == nil {
+			return fmt.Errorf("no partner found with identifier (%s, %s)", typeId, value.(string))
+		}
+	} else {
+		return fmt.Errorf("no partner name, host, or identifier specified")
+	}
+
+	// The host and list lookups only return a summary, so retrieve the full partner
+	partner, err = client.GetPartner(*partner.Id)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(*partner.Id)
+	d.Set("name", *partner.Name)
+	if partner.Description != nil {
+		d.Set("description", *partner.Description)
+	}
+	if partner.WebsiteUrl != nil {
+		d.Set("website_url", *partner.WebsiteUrl)
+	}
+
+	identifiers, err := client.ListPartnerIdentifiers(*partner.Id)
+	if err != nil {
+		return err
+	}
+	if err = d.Set("identifier", flattenIdentifiers(identifiers)); err != nil {
+		return err
+	}
 
-		d.SetId(*partner.Id)
-		d.Set("name", *partner.Name)
+	x12, err := findPartnerInboundX12Configuration(client, *partner.Id)
+	if err != nil {
+		return err
+	}
+	if x12 != nil {
+		if err = d.Set("x12_inbound_config", flattenX12InboundConfig(x12)); err != nil {
+			return err
+		}
+	}
 
-		return nil
+	contacts, err := client.GetPartnerContacts(*partner.Id)
+	if err != nil {
+		return err
+	}
+	if err = d.Set("contact", flattenContacts(contacts)); err != nil {
+		return err
+	}
+
+	address, err := findPartnerAddress(client, *partner.Id)
+	if err != nil {
+		return err
+	}
+	if address != nil {
+		if err = d.Set("address", flattenAddress(address)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPartnerByIdentifier returns the partner in the client's current environment that owns
+// the identifier with the given qualifier and value, or nil if no partner owns it.
+func getPartnerByIdentifier(client *muleb2b.Client, identifierTypeId, value string) (*muleb2b.Partner, error) {
+	partners, err := client.ListPartners()
+	if err != nil {
+		return nil, err
+	}
+
+	if partners != nil {
+		for _, partner := range *partners {
+			if partner.Id == nil {
+				continue
+			}
+			identifier, err := client.GetPartnerIdentifierByQualifierIdAndValue(*partner.Id, identifierTypeId, value)
+			if err != nil {
+				return nil, err
+			}
+			if identifier != nil {
+				return &partner, nil
+			}
+		}
 	}
 
-	return fmt.Errorf("no partner name specified")
+	return nil, nil
 }
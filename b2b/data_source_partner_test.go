@@ -2,6 +2,7 @@ package b2b
 
 import (
 	"fmt"
+	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"os"
@@ -50,3 +51,87 @@ func testDataSourcePartner_InitialCheck() resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestAccMuleB2bPartnerDS_identifier(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	number := acctest.RandIntRange(100, 10000)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourcePartner_IdentifierConfig(envName, name, number),
+				Check:  testDataSourcePartner_IdentifierCheck(name),
+			},
+		},
+	})
+}
+
+func testDataSourcePartner_IdentifierConfig(envName, name string, number int) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  description    = "partner data source test"
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d"
+  }
+  address {
+    address_line_1 = "123 Main Street"
+    city = "Anytown"
+    state = "NY"
+    country = "US"
+    postal_code = "12345"
+  }
+}
+
+data "muleb2b_partner" "test" {
+  environment_id     = data.muleb2b_environment.sbx.id
+  identifier_type_id = data.muleb2b_identifier_type.duns.id
+  identifier_value   = "%d"
+  depends_on         = [muleb2b_partner.test]
+}`, envName, name, number, number)
+}
+
+func testDataSourcePartner_IdentifierCheck(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		partnerId := s.Modules[0].Resources["muleb2b_partner.test"].Primary.ID
+		ds := s.Modules[0].Resources["data.muleb2b_partner.test"].Primary
+
+		if ds.ID != partnerId {
+			return fmt.Errorf("partner ID (%s) does not match expected (%s)", ds.ID, partnerId)
+		}
+
+		if ds.Attributes["name"] != name {
+			return fmt.Errorf("partner name (%s) does not match expected (%s)", ds.Attributes["name"], name)
+		}
+
+		if ds.Attributes["description"] != "partner data source test" {
+			return fmt.Errorf("partner description was not read")
+		}
+
+		if ds.Attributes["identifier.#"] != "1" {
+			return fmt.Errorf("expected 1 identifier, found %s", ds.Attributes["identifier.#"])
+		}
+
+		if ds.Attributes["address.#"] != "1" {
+			return fmt.Errorf("expected 1 address, found %s", ds.Attributes["address.#"])
+		}
+
+		if ds.Attributes["x12_inbound_config.#"] != "1" {
+			return fmt.Errorf("x12_inbound_config was not read")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,208 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"regexp"
+	"strings"
+)
+
+func dataSourcePartners() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePartnersRead,
+		Schema: map[string]*schema.Schema{
+			"environment_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the environment to list Partners in",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegex,
+				Description:  "Regular expression the partner name must match",
+			},
+			"identifier_type_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include partners that have an identifier of this type",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include partners with this status, e.g. ACTIVE",
+			},
+			"has_x12_inbound_config": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only include partners that have (true) or do not have (false) an X12 inbound configuration",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the matching partners",
+			},
+			"partners": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching partners",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the partner",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the partner",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the partner",
+						},
+						"website_url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL of the partner's website",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the partner",
+						},
+						"host": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "True if the partner is the host",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateRegex(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+		return warnings, errors
+	}
+
+	if _, err := regexp.Compile(v); err != nil {
+		errors = append(errors, fmt.Errorf("%q is not a valid regular expression: %s", key, err))
+	}
+	return warnings, errors
+}
+
+func dataSourcePartnersRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	envId := d.Get("environment_id").(string)
+	client.SetEnvironment(envId)
+
+	partners, err := client.ListPartners()
+	if err != nil {
+		return err
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	identifierTypeId := d.Get("identifier_type_id").(string)
+	status := d.Get("status").(string)
+	hasX12, x12Ok := d.GetOkExists("has_x12_inbound_config")
+
+	var ids []string
+	var out []interface{}
+
+	if partners != nil {
+		for _, partner := range *partners {
+			if partner.Id == nil || partner.Name == nil {
+				continue
+			}
+
+			if nameRegex != nil && !nameRegex.MatchString(*partner.Name) {
+				continue
+			}
+
+			if status != "" && (partner.Status == nil || partner.Status.Status == nil || !strings.EqualFold(*partner.Status.Status, status)) {
+				continue
+			}
+
+			if identifierTypeId != "" {
+				found, err := partnerHasIdentifierType(client, *partner.Id, identifierTypeId)
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+			}
+
+			if x12Ok {
+				x12, err := findPartnerInboundX12Configuration(client, *partner.Id)
+				if err != nil {
+					return err
+				}
+				if (x12 != nil) != hasX12.(bool) {
+					continue
+				}
+			}
+
+			ids = append(ids, *partner.Id)
+			out = append(out, flattenPartnerSummary(&partner))
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s-%d", envId, hashcode.String(strings.Join(ids, ","))))
+	if err = d.Set("ids", ids); err != nil {
+		return err
+	}
+	if err = d.Set("partners", out); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func partnerHasIdentifierType(client *muleb2b.Client, partnerId, identifierTypeId string) (bool, error) {
+	identifiers, err := client.ListPartnerIdentifiers(partnerId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, identifier := range identifiers {
+		if identifier.IdentifierTypeQualifierId != nil && *identifier.IdentifierTypeQualifierId == identifierTypeId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func flattenPartnerSummary(partner *muleb2b.Partner) map[string]interface{} {
+	m := make(map[string]interface{})
+	m["id"] = *partner.Id
+	m["name"] = *partner.Name
+	if partner.Description != nil {
+		m["description"] = *partner.Description
+	}
+	if partner.WebsiteUrl != nil {
+		m["website_url"] = *partner.WebsiteUrl
+	}
+	if partner.Status != nil && partner.Status.Status != nil {
+		m["status"] = *partner.Status.Status
+	}
+	if partner.HostFlag != nil {
+		m["host"] = *partner.HostFlag
+	}
+	return m
+}
@@ -0,0 +1,91 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"os"
+	"testing"
+)
+
+func TestAccMuleB2bPartnersDS(t *testing.T) {
+	prefix := "accTest-" + acctest.RandString(5)
+	number := acctest.RandIntRange(100, 10000)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourcePartners_InitialConfig(envName, prefix, number),
+				Check:  testDataSourcePartners_InitialCheck(),
+			},
+		},
+	})
+}
+
+func testDataSourcePartners_InitialConfig(envName, prefix string, number int) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+resource "muleb2b_partner" "one" {
+  name           = "%s-one"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d1"
+  }
+}
+
+resource "muleb2b_partner" "two" {
+  name           = "%s-two"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d2"
+  }
+}
+
+data "muleb2b_partners" "all" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name_regex     = "^%s-"
+  depends_on     = [muleb2b_partner.one, muleb2b_partner.two]
+}
+
+data "muleb2b_partners" "one" {
+  environment_id         = data.muleb2b_environment.sbx.id
+  name_regex             = "^%s-one$"
+  identifier_type_id     = data.muleb2b_identifier_type.duns.id
+  has_x12_inbound_config = true
+  depends_on             = [muleb2b_partner.one, muleb2b_partner.two]
+}`, envName, prefix, number, prefix, number, prefix, prefix)
+}
+
+func testDataSourcePartners_InitialCheck() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		all := s.Modules[0].Resources["data.muleb2b_partners.all"].Primary
+		one := s.Modules[0].Resources["data.muleb2b_partners.one"].Primary
+		oneId := s.Modules[0].Resources["muleb2b_partner.one"].Primary.ID
+
+		if all.Attributes["ids.#"] != "2" {
+			return fmt.Errorf("expected 2 partners, found %s", all.Attributes["ids.#"])
+		}
+
+		if one.Attributes["ids.#"] != "1" {
+			return fmt.Errorf("expected 1 partner, found %s", one.Attributes["ids.#"])
+		}
+
+		if one.Attributes["partners.0.id"] != oneId {
+			return fmt.Errorf("partner ID (%s) does not match expected (%s)", one.Attributes["partners.0.id"], oneId)
+		}
+
+		return nil
+	}
+}
@@ -7,6 +7,13 @@ import (
 	"os"
 )
 
+// providerMeta is passed to every resource and data source. It holds the logged in client and the provider settings
+type providerMeta struct {
+	client             *muleb2b.Client
+	identifierRules    []identifierRule
+	partnerIdentifiers *partnerIdentifiersCache
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -32,20 +39,63 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				Description: "The password for the user for the API operations",
 			},
+			"identifier_rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Custom format rule for identifier values, overrides the built-in rule for the same identifier type and qualifier",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"identifier_type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the identifier type the rule applies to, e.g. X12-ISA",
+						},
+						"qualifier_code": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Qualifier code the rule applies to. Applies to all qualifiers of the identifier type if not set",
+						},
+						"pattern": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegex,
+							Description:  "Regular expression the identifier value must match",
+						},
+						"min_length": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Minimum length of the identifier value",
+						},
+						"max_length": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum length of the identifier value",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Description of the expected format, used in validation errors",
+						},
+					},
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"muleb2b_partner":       resourcePartner(),
-			"muleb2b_endpoint":      resourceEndpoint(),
-			"muleb2b_document":      resourceDocument(),
-			"muleb2b_document_flow": resourceDocumentFlow(),
-			"muleb2b_identifier":    resourceIdentifier(),
-			"muleb2b_certificate":   resourceCertificate(),
+			"muleb2b_partner":           resourcePartner(),
+			"muleb2b_endpoint":          resourceEndpoint(),
+			"muleb2b_document":          resourceDocument(),
+			"muleb2b_document_flow":     resourceDocumentFlow(),
+			"muleb2b_identifier":        resourceIdentifier(),
+			"muleb2b_certificate":       resourceCertificate(),
+			"muleb2b_partner_promotion": resourcePartnerPromotion(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"muleb2b_environment":     dataSourceEnvironment(),
 			"muleb2b_ediDocumentType": dataSourceEdiDocumentType(),
 			"muleb2b_partner":         dataSourcePartner(),
+			"muleb2b_partners":        dataSourcePartners(),
 			"muleb2b_identifier_type": dataSourceIdentifierType(),
+			"muleb2b_endpoint":        dataSourceEndpoint(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -97,5 +147,10 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, err
 	}
 
-	return client, nil
+	rules, err := expandIdentifierRules(d.Get("identifier_rule"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &providerMeta{client: client, identifierRules: rules, partnerIdentifiers: &partnerIdentifiersCache{}}, nil
 }
@@ -1,7 +1,6 @@
 package b2b
 
 import (
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -41,7 +40,7 @@ func resourceCertificate() *schema.Resource {
 }
 
 func resourceCertificateCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -62,7 +61,7 @@ func resourceCertificateCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -80,7 +79,7 @@ func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
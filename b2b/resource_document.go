@@ -13,6 +13,8 @@ func resourceDocument() *schema.Resource {
 		Update: resourceDocumentUpdate,
 		Delete: resourceDocumentDelete,
 
+		CustomizeDiff: resourceDocumentCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -22,22 +24,38 @@ func resourceDocument() *schema.Resource {
 			"partner_id": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "The ID of the partner to create the document under",
 			},
 			"environment_id": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "The ID of the environment to create the document under",
 			},
 			"edi_document_type_id": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "The ID of the document type",
 			},
 			"schema_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"schema_path"},
+				DiffSuppressFunc: suppressEquivalentSchema,
+				Description:      "Base64 Encoded contents of the schema file. Not needed unless using a custom schema.",
+			},
+			"schema_path": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"schema_file"},
+				Description:   "Path of the custom schema file, read when the document is created or updated",
+			},
+			"schema_hash": {
 				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Base64 Encoded contents of the schema file. Not needed unless using a custom schema.",
+				Computed:    true,
+				Description: "SHA-256 hash of the schema file read from schema_path",
 			},
 			"custom_schema_id": {
 				Type:        schema.TypeString,
@@ -49,7 +67,7 @@ func resourceDocument() *schema.Resource {
 }
 
 func resourceDocumentCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -57,19 +75,15 @@ func resourceDocumentCreate(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("name").(string)
 	partnerId := d.Get("partner_id").(string)
 	docTypeId := d.Get("edi_document_type_id").(string)
-	schemaFile, ok := d.GetOk("schema_file")
 
 	doc := muleb2b.Document{
 		Name:              muleb2b.String(name),
 		EdiDocumentTypeId: muleb2b.String(docTypeId),
 	}
 
-	if ok {
-		doc.SchemaContent = muleb2b.String(schemaFile.(string))
-		doc.SchemaType = muleb2b.String("customSchemaType")
-		doc.Standard = muleb2b.Boolean(false)
-	} else {
-		doc.Standard = muleb2b.Boolean(true)
+	err := readDocumentSchema(d, &doc)
+	if err != nil {
+		return err
 	}
 
 	id, err := client.CreateDocument(partnerId, &doc)
@@ -85,8 +99,34 @@ func resourceDocumentCreate(d *schema.ResourceData, m interface{}) error {
 	return resourceDocumentRead(d, m)
 }
 
+// readDocumentSchema sets the custom schema of a document from schema_file or the file at schema_path, and stores
+// the hash of the file in schema_hash
+func readDocumentSchema(d *schema.ResourceData, doc *muleb2b.Document) error {
+	content := ""
+	hash := ""
+	if path, ok := d.GetOk("schema_path"); ok {
+		var err error
+		content, hash, err = readSchemaPath(path.(string))
+		if err != nil {
+			return err
+		}
+	} else if schemaFile, ok := d.GetOk("schema_file"); ok {
+		content = schemaFile.(string)
+	}
+	d.Set("schema_hash", hash)
+
+	if content != "" {
+		doc.SchemaContent = muleb2b.String(content)
+		doc.SchemaType = muleb2b.String("customSchemaType")
+		doc.Standard = muleb2b.Boolean(false)
+	} else {
+		doc.Standard = muleb2b.Boolean(true)
+	}
+	return nil
+}
+
 func resourceDocumentRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
 	client.SetEnvironment(envId)
@@ -94,16 +134,36 @@ func resourceDocumentRead(d *schema.ResourceData, m interface{}) error {
 	id := d.Id()
 	partnerId := d.Get("partner_id").(string)
 
+	// A document deleted outside of Terraform is removed from the state, so it is created again
 	doc, err := client.GetDocumentById(partnerId, id)
-	if err != nil {
+	if isNotFoundError(err) || (err == nil && doc == nil) {
+		d.SetId("")
+		return nil
+	} else if err != nil {
 		return err
 	}
 
-	if doc != nil {
-		d.Set("partner_id", partnerId)
+	if doc.Name != nil {
+		d.Set("name", *doc.Name)
+	}
+	d.Set("partner_id", partnerId)
+	if doc.EdiDocumentTypeId != nil {
 		d.Set("edi_document_type_id", *doc.EdiDocumentTypeId)
-		if doc.SchemaContent != nil {
-			d.Set("schema_file", *doc.SchemaContent)
+	}
+	if path, ok := d.GetOk("schema_path"); ok {
+		// Only the hash is kept. It is replaced by the hash of the server's schema when that schema is not
+		// equivalent to the file anymore, so the plan sends the file again
+		if doc.SchemaContent == nil {
+			d.Set("schema_hash", "")
+		} else if content, _, err := readSchemaPath(path.(string)); err == nil && !schemaContentsEquivalent(content, *doc.SchemaContent) {
+			d.Set("schema_hash", schemaHash(decodeSchemaContent(*doc.SchemaContent)))
+		}
+		if doc.CustomSchemaId != nil {
+			d.Set("custom_schema_id", *doc.CustomSchemaId)
+		}
+	} else if doc.SchemaContent != nil {
+		d.Set("schema_file", *doc.SchemaContent)
+		if doc.CustomSchemaId != nil {
 			d.Set("custom_schema_id", *doc.CustomSchemaId)
 		}
 	}
@@ -111,9 +171,66 @@ func resourceDocumentRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceDocumentUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceDocumentCreate(d, m)
+	client := m.(*providerMeta).client
+
+	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
+	client.SetEnvironment(envId)
+
+	// The partner and document type force a new document, only the name and schema change in place
+	partnerId := d.Get("partner_id").(string)
+	doc := muleb2b.Document{
+		Id:                muleb2b.String(d.Id()),
+		Name:              muleb2b.String(d.Get("name").(string)),
+		EdiDocumentTypeId: muleb2b.String(d.Get("edi_document_type_id").(string)),
+	}
+
+	err := readDocumentSchema(d, &doc)
+	if err != nil {
+		return err
+	}
+	if customSchemaId, ok := d.GetOk("custom_schema_id"); ok && !*doc.Standard {
+		doc.CustomSchemaId = muleb2b.String(customSchemaId.(string))
+	}
+
+	err = client.UpdateDocument(partnerId, &doc)
+	if err != nil {
+		return err
+	}
+
+	return resourceDocumentRead(d, m)
 }
 
 func resourceDocumentDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+
+	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
+	client.SetEnvironment(envId)
+
+	err := client.DeleteDocumentById(d.Get("partner_id").(string), d.Id())
+
+	return err
+}
+
+// resourceDocumentCustomizeDiff plans the hash of the file at schema_path, so a changed file updates the document.
+// A file that can't be read yet, e.g. because it is generated during the apply, is read when the document is updated
+func resourceDocumentCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	path, ok := d.GetOk("schema_path")
+	if !ok {
+		if d.Get("schema_hash").(string) != "" {
+			return d.SetNew("schema_hash", "")
+		}
+		return nil
+	}
+	if !d.NewValueKnown("schema_path") {
+		return d.SetNewComputed("schema_hash")
+	}
+
+	_, hash, err := readSchemaPath(path.(string))
+	if err != nil {
+		return d.SetNewComputed("schema_hash")
+	}
+	if hash != d.Get("schema_hash").(string) {
+		return d.SetNew("schema_hash", hash)
+	}
 	return nil
 }
@@ -142,7 +142,7 @@ func validateDocumentFlowDirection(value interface{}, key string) (warnings []st
 }
 
 func resourceDocumentFlowCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -199,7 +199,7 @@ func resourceDocumentFlowCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceDocumentFlowRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
@@ -233,7 +233,7 @@ func resourceDocumentFlowRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceDocumentFlowUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
 	client.SetEnvironment(envId)
 	cFlow, err := client.GetDocumentFlowById(d.Id())
@@ -270,7 +270,7 @@ func resourceDocumentFlowUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceDocumentFlowDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
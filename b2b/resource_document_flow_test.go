@@ -2,10 +2,12 @@ package b2b
 
 import (
 	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"os"
+	"regexp"
 	"testing"
 )
 
@@ -28,6 +30,140 @@ func TestAccMuleB2bResourceDocumentFlow(t *testing.T) {
 	})
 }
 
+func TestAccMuleB2bResourceDocumentFlow_endpointInUse(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	var endpointId, flowId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceDocumentFlow_EndpointInUseConfig(envName, name, false),
+				Check:  testResourceDocumentFlow_CreateFlowUsingEndpoint(name, &endpointId, &flowId),
+			},
+			{
+				Config:      testResourceDocumentFlow_EndpointInUseConfig(envName, name, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("referenced by the document flows " + name),
+			},
+			{
+				Config: testResourceDocumentFlow_EndpointInUseConfig(envName, name, true),
+			},
+			{
+				Config: testResourceDocumentFlow_EndpointRemovedConfig(envName, name),
+				Check:  testResourceDocumentFlow_CheckEndpointDetached(name, &endpointId, &flowId),
+			},
+		},
+	})
+}
+
+// testResourceDocumentFlow_CreateFlowUsingEndpoint creates a document flow receiving on the endpoint outside of
+// Terraform, so destroying the endpoint is not ordered after the flow
+func testResourceDocumentFlow_CreateFlowUsingEndpoint(name string, endpointId, flowId *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		endpointState := s.Modules[0].Resources["muleb2b_endpoint.test"].Primary
+		partnerState := s.Modules[0].Resources["muleb2b_partner.test"].Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		client.SetEnvironment(endpointState.Attributes["environment_id"])
+
+		id, err := client.CreateDocumentFlow(&muleb2b.DocumentFlow{
+			Name:          muleb2b.String(name),
+			Direction:     muleb2b.String("INBOUND"),
+			PartnerFromId: muleb2b.String(partnerState.ID),
+			PartnerToId:   muleb2b.String(endpointState.Attributes["partner_id"]),
+		})
+		if err != nil {
+			return err
+		}
+		*flowId = *id
+		*endpointId = endpointState.ID
+
+		flow, err := client.GetDocumentFlowById(*id)
+		if err != nil {
+			return err
+		}
+		(*flow.Configurations[0]).ReceivingEndpointId = muleb2b.String(endpointState.ID)
+		(*flow.Configurations[0]).DocumentMapping = []*muleb2b.Mapping{}
+		_, err = client.UpdateDocumentFlow(flow)
+		return err
+	}
+}
+
+// testResourceDocumentFlow_CheckEndpointDetached checks that the endpoint destroyed with force_destroy is no longer
+// referenced by the document flow, then deletes the flow so the partner can be destroyed
+func testResourceDocumentFlow_CheckEndpointDetached(name string, endpointId, flowId *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		partnerState := s.Modules[0].Resources["muleb2b_partner.test"].Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		client.SetEnvironment(partnerState.Attributes["environment_id"])
+
+		flow, err := client.GetDocumentFlowById(*flowId)
+		if err != nil {
+			return err
+		}
+		for _, config := range flow.Configurations {
+			if config != nil && len(documentFlowEndpointReferences(config, *endpointId)) > 0 {
+				return fmt.Errorf("document flow %s still references the destroyed endpoint (%s)", name, *endpointId)
+			}
+		}
+
+		return client.DeleteDocumentFlow(*flowId)
+	}
+}
+
+func testResourceDocumentFlow_EndpointInUseConfig(envName, name string, forceDestroy bool) string {
+	return fmt.Sprintf(`%s
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "receive"
+  type = "http"
+  partner_id = data.muleb2b_partner.host.id
+  environment_id = data.muleb2b_environment.sbx.id
+  force_destroy = %t
+  http_config {
+    server_address = "accTest.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode  {
+      type = "none"
+    }
+  }
+
+  # Destroy the endpoint first, so the partner referenced by the document flow is kept when the destroy fails
+  depends_on = [muleb2b_partner.test]
+}
+`, testResourceDocumentFlow_EndpointRemovedConfig(envName, name), name, forceDestroy)
+}
+
+func testResourceDocumentFlow_EndpointRemovedConfig(envName, name string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_partner" "host" {
+  environment_id = data.muleb2b_environment.sbx.id
+  host = true
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+`, envName, name, name)
+}
+
 func testResourceDocumentFlow_InitialConfig(envName, name string) string {
 	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
   name = "%s"
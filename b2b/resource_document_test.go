@@ -1,12 +1,15 @@
 package b2b
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -14,8 +17,9 @@ func TestAccMuleB2bDocument(t *testing.T) {
 	name := "accTest-" + acctest.RandString(5)
 	envName := os.Getenv("TEST_ENV_NAME")
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDocumentDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testResourceDocument_InitialConfig(envName, name),
@@ -79,7 +83,7 @@ func testResourceDocument_InitialCheck() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		_, err := client.GetDocumentById(partner, id)
 		if err != nil {
 			return err
@@ -139,7 +143,7 @@ func testResourceDocument_UpdateCheck() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		doc, err := client.GetDocumentById(partner, id)
 		if err != nil {
 			return err
@@ -152,3 +156,188 @@ func testResourceDocument_UpdateCheck() resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestAccMuleB2bDocument_rename(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDocumentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceDocument_InitialConfig(envName, name),
+				Check: func(s *terraform.State) error {
+					id = s.Modules[0].Resources["muleb2b_document.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				Config: strings.Replace(testResourceDocument_InitialConfig(envName, name), fmt.Sprintf(`name = "%s"
+  partner_id`, name), fmt.Sprintf(`name = "%s-renamed"
+  partner_id`, name), 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_document.test", "name", name+"-renamed"),
+					testResourceDocument_CheckName(&id, name+"-renamed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMuleB2bDocument_deleted(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	var envId, partnerId, id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDocumentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceDocument_InitialConfig(envName, name),
+				Check: func(s *terraform.State) error {
+					instanceState := s.Modules[0].Resources["muleb2b_document.test"].Primary
+					envId, partnerId, id = instanceState.Attributes["environment_id"], instanceState.Attributes["partner_id"], instanceState.ID
+					return nil
+				},
+			},
+			{
+				// The document is deleted outside of Terraform, the refresh removes it from the state and the plan
+				// creates it again
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*providerMeta).client
+					client.SetEnvironment(envId)
+					if err := client.DeleteDocumentById(partnerId, id); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testResourceDocument_InitialConfig(envName, name),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testResourceDocument_CheckName(id *string, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		instanceState := s.Modules[0].Resources["muleb2b_document.test"].Primary
+		if instanceState.ID != *id {
+			return fmt.Errorf("document was replaced instead of renamed")
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		doc, err := client.GetDocumentById(instanceState.Attributes["partner_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if doc == nil || doc.Name == nil || *doc.Name != name {
+			return fmt.Errorf("name did not update")
+		}
+		return nil
+	}
+}
+
+func testAccCheckDocumentDestroy(s *terraform.State) error {
+	cli := testAccProvider.Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "muleb2b_document" {
+			continue
+		}
+
+		_, err := cli.GetDocumentById(rs.Primary.Attributes["partner_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("document (%s) still exists", rs.Primary.ID)
+		}
+
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAccMuleB2bDocument_schemaPath(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+
+	schemaFile, err := ioutil.TempFile("", "accTest-schema-*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaFile.Close()
+	defer os.Remove(schemaFile.Name())
+
+	writeSchema := func(schema string) {
+		if err := ioutil.WriteFile(schemaFile.Name(), []byte(schema), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schemaV1 := `{"title": "Person", "type": "object"}`
+	schemaV2 := `{"title": "Person", "type": "object", "required": ["firstName"]}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDocumentDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { writeSchema(schemaV1) },
+				Config:    testResourceDocument_ConfigSchemaPath(envName, name, schemaFile.Name()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_document.test", "schema_hash", testResourceDocument_SchemaHash(schemaV1)),
+					resource.TestCheckResourceAttr("muleb2b_document.test", "schema_file", ""),
+				),
+			},
+			{
+				PreConfig: func() { writeSchema(schemaV2) },
+				Config:    testResourceDocument_ConfigSchemaPath(envName, name, schemaFile.Name()),
+				Check:     resource.TestCheckResourceAttr("muleb2b_document.test", "schema_hash", testResourceDocument_SchemaHash(schemaV2)),
+			},
+		},
+	})
+}
+
+func testResourceDocument_ConfigSchemaPath(envName, name, path string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+data "muleb2b_ediDocumentType" "json" {
+  environment_id = data.muleb2b_environment.sbx.id
+  format_type = "JSON"
+  format_version = "V1"
+  document_name = "JSON"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_document" "test" {
+  name = "%s"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  edi_document_type_id = data.muleb2b_ediDocumentType.json.id
+  schema_path = "%s"
+}`, envName, name, name, name, path)
+}
+
+func testResourceDocument_SchemaHash(schema string) string {
+	sum := sha256.Sum256([]byte(schema))
+	return hex.EncodeToString(sum[:])
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"mime"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
@@ -14,6 +18,11 @@ func resourceEndpoint() *schema.Resource {
 		Update: resourceEndpointUpdate,
 		Delete: resourceEndpointDelete,
 
+		CustomizeDiff: resourceEndpointCustomizeDiff,
+
+		SchemaVersion: 1,
+		MigrateState:  resourceEndpointMigrateState,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -30,7 +39,7 @@ func resourceEndpoint() *schema.Resource {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validateType,
-				Description:  "The type of endpoint, http or sftp",
+				Description:  "The type of endpoint, http, sftp, as2, ftp, anypoint_mq, s3, or azure_blob",
 			},
 			"partner_id": {
 				Type:        schema.TypeString,
@@ -53,10 +62,59 @@ func resourceEndpoint() *schema.Resource {
 				Optional:    true,
 				Description: "ID of the certificate to use when a certificate is needed",
 			},
-			"http_config": {
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Detach the endpoint from the document flows that reference it when it is destroyed",
+			},
+			"retry_policy": {
 				Type:        schema.TypeSet,
 				Optional:    true,
 				MaxItems:    1,
+				Description: "How failed deliveries of a send endpoint are retried",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3,
+							ValidateFunc: validateIntBetween(1, 100),
+							Description:  "Number of delivery attempts, including the first one",
+						},
+						"backoff_interval": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      5000,
+							ValidateFunc: validateIntBetween(0, 86400000),
+							Description:  "Time to wait between delivery attempts (ms)",
+						},
+						"retryable_status_codes": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeInt,
+								ValidateFunc: validateIntBetween(100, 599),
+							},
+							Description: "HTTP status codes of a response that are retried, e.g. 502, 503, 504. http and as2 endpoints only",
+						},
+						"dead_letter_endpoint_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the send endpoint deliveries are sent to when all attempts failed",
+						},
+					},
+				},
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the endpoint, built from its configuration",
+			},
+			"http_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
 				Description: "HTTP configuration",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -66,24 +124,30 @@ func resourceEndpoint() *schema.Resource {
 							Default:     "http",
 							Description: "name of the endpoint configuration",
 						},
+						"url": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateEndpointUrl("http", "https"),
+							Description:  "URL of the HTTP service, e.g. https://edi.partner.com:8443/inbound. Conflicts with protocol, server_address, server_port, and path",
+						},
 						"server_address": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "Address of the HTTP service",
 						},
 						"server_port": {
 							Type:        schema.TypeInt,
-							Required:    true,
+							Optional:    true,
 							Description: "Port of the HTTP service",
 						},
 						"path": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "Path of the HTTP service",
 						},
 						"protocol": {
 							Type:         schema.TypeString,
-							Required:     true,
+							Optional:     true,
 							ValidateFunc: validateEndpointHttpProtocol,
 							Description:  "Protocol of the service. http or https",
 						},
@@ -99,33 +163,41 @@ func resourceEndpoint() *schema.Resource {
 							Default:     30000,
 							Description: "Time to wait before the connection is considered idle (ms)",
 						},
-						"auth_mode": endpointAuthModeSchema(),
-						"tls_context": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							MaxItems: 1,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"insecure": {
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     false,
-										Description: "Whether or not the connection is insecure",
-									},
-									"need_certificate": {
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     false,
-										Description: "Whether or not a certificate is needed",
-									},
-								},
-							},
+						"method": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "POST",
+							ValidateFunc: validateOneOf("POST", "PUT", "PATCH"),
+							Description:  "HTTP method used to send documents: POST, PUT, or PATCH. Send endpoints only",
+						},
+						"headers": {
+							Type:         schema.TypeMap,
+							Optional:     true,
+							Elem:         &schema.Schema{Type: schema.TypeString},
+							ValidateFunc: validateHttpHeaders,
+							Description:  "Static headers added to every request. Send endpoints only",
 						},
+						"content_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateContentType,
+							Description:  "Content-Type of the requests, e.g. application/edi-x12. Send endpoints only",
+						},
+						"query_params": {
+							Type:         schema.TypeMap,
+							Optional:     true,
+							Elem:         &schema.Schema{Type: schema.TypeString},
+							ValidateFunc: validateQueryParams,
+							Description:  "Query parameters added to every request. Send endpoints only",
+						},
+						"auth_mode":   endpointAuthModeSchema(),
+						"tls_context": endpointTlsContextSchema(),
+						"proxy":       endpointProxySchema(),
 					},
 				},
 			},
 			"sftp_config": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Optional: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
@@ -136,14 +208,20 @@ func resourceEndpoint() *schema.Resource {
 							Default:     "sftp",
 							Description: "name of the endpoint configuration",
 						},
+						"url": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateEndpointUrl("sftp"),
+							Description:  "URL of the sftp server, e.g. sftp://host:22/in. Conflicts with server_address, server_port, and path",
+						},
 						"server_address": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "Address of the sftp server",
 						},
 						"server_port": {
 							Type:        schema.TypeInt,
-							Required:    true,
+							Optional:    true,
 							Description: "Port of the sftp server",
 						},
 						"archive_path": {
@@ -163,108 +241,951 @@ func resourceEndpoint() *schema.Resource {
 							Default:     1000,
 							Description: "Time to wait between checking for new files",
 						},
+						"path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to look for new files",
+						},
+						"host_key_fingerprint": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Fingerprint the server's host key must match, e.g. SHA256:<base64>",
+						},
+						"known_hosts": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "known_hosts content used to verify the server's host key",
+						},
+						"working_directory": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Directory relative paths are resolved against",
+						},
+						"file_name_pattern": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Glob the names of read files must match, e.g. *.edi. Receive endpoints only",
+						},
+						"delete_after_read": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether read files are deleted instead of moved to archive_path. Receive endpoints only",
+						},
+						"min_file_age": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Time a file must not have been modified for before it is read (ms). Receive endpoints only",
+						},
+						"output_file_name": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateOutputFileName,
+							Description:  "Template of the names of written files, e.g. ${partner}_${docType}_${timestamp}.edi. Send endpoints only",
+						},
+						"write_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "direct",
+							ValidateFunc: validateOneOf("direct", "temp_file_rename"),
+							Description:  "How files are written: direct, or temp_file_rename to write a temporary file and rename it once complete. Send endpoints only",
+						},
+						"file_exists_action": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "overwrite",
+							ValidateFunc: validateOneOf("overwrite", "append", "fail"),
+							Description:  "What happens when a written file already exists: overwrite, append, or fail. Send endpoints only",
+						},
+						"auth_mode": endpointSftpAuthModeSchema(),
+						"proxy":     endpointProxySchema(),
+					},
+				},
+			},
+			"ftp_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "FTP and FTPS configuration",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"config_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "ftp",
+							Description: "name of the endpoint configuration",
+						},
+						"server_address": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Address of the ftp server",
+						},
+						"server_port": {
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "Port of the ftp server",
+						},
+						"archive_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to move read files into",
+						},
+						"size_check_wait_time": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1000,
+							Description: "Time to wait to check the file size",
+						},
+						"polling_frequency": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1000,
+							Description: "Time to wait between checking for new files",
+						},
 						"path": {
 							Type:        schema.TypeString,
 							Required:    true,
 							Description: "Path to look for new files",
 						},
-						"auth_mode": endpointAuthModeSchema(),
+						"passive_mode": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether or not passive mode is used for data connections",
+						},
+						"transfer_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "binary",
+							ValidateFunc: validateOneOf("binary", "ascii"),
+							Description:  "File transfer mode: binary or ascii",
+						},
+						"ftps_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "none",
+							ValidateFunc: validateOneOf("none", "explicit", "implicit"),
+							Description:  "FTPS mode: none for plain FTP, explicit, or implicit",
+						},
+						"auth_mode":   endpointAuthModeSchema(),
+						"tls_context": endpointTlsContextSchema(),
+						"proxy":       endpointProxySchema(),
+					},
+				},
+			},
+			"as2_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "AS2 configuration",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"config_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "as2",
+							Description: "name of the endpoint configuration",
+						},
+						"server_address": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Address of the AS2 service",
+						},
+						"server_port": {
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "Port of the AS2 service",
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path of the AS2 service",
+						},
+						"protocol": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateEndpointHttpProtocol,
+							Description:  "Protocol of the service. http or https",
+						},
+						"as2_from": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "AS2-From identifier of the sender",
+						},
+						"as2_to": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "AS2-To identifier of the receiver",
+						},
+						"signing_algorithm": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "sha256",
+							ValidateFunc: validateOneOf("none", "sha1", "sha256", "sha384", "sha512"),
+							Description:  "Algorithm used to sign messages: none, sha1, sha256, sha384, or sha512",
+						},
+						"encryption_algorithm": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "aes256_cbc",
+							ValidateFunc: validateOneOf("none", "3des", "aes128_cbc", "aes192_cbc", "aes256_cbc"),
+							Description:  "Algorithm used to encrypt messages: none, 3des, aes128_cbc, aes192_cbc, or aes256_cbc",
+						},
+						"compression": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether or not messages are compressed",
+						},
+						"mdn_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "sync",
+							ValidateFunc: validateOneOf("sync", "async"),
+							Description:  "Whether the MDN is returned synchronously or asynchronously: sync or async",
+						},
+						"mdn_signed": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether or not the MDN is signed",
+						},
+						"mic_algorithm": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "sha256",
+							ValidateFunc: validateOneOf("md5", "sha1", "sha256", "sha384", "sha512"),
+							Description:  "Algorithm used to calculate the MIC of signed MDNs: md5, sha1, sha256, sha384, or sha512",
+						},
+						"async_mdn_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "URL asynchronous MDNs are sent to, required when mdn_mode is async",
+						},
+						"signing_certificate_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the certificate used to sign or verify messages",
+						},
+						"encryption_certificate_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the certificate used to encrypt or decrypt messages",
+						},
+						"response_timeout": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     15000,
+							Description: "Time to wait for a service response (ms)",
+						},
+						"tls_context": endpointTlsContextSchema(),
 					},
 				},
 			},
+			"anypoint_mq_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Anypoint MQ configuration",
+				Elem:        endpointAnypointMqSchema(),
+			},
+			"s3_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Amazon S3 configuration",
+				Elem:        endpointS3Schema(),
+			},
+			"azure_blob_config": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Azure Blob Storage configuration",
+				Elem:        endpointAzureBlobSchema(),
+			},
+		},
+	}
+}
+
+// endpointAnypointMqSchema is the anypoint_mq_config block. The client app credentials are sent as a
+// client_credentials auth mode, so they are kept in the block itself instead of an auth_mode block
+func endpointAnypointMqSchema() *schema.Resource {
+	mq := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"config_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "anypoint_mq",
+				Description: "name of the endpoint configuration",
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the queue or exchange messages are sent to or consumed from",
+			},
+			"destination_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "queue",
+				ValidateFunc: validateOneOf("queue", "exchange"),
+				Description:  "Type of the destination: queue or exchange",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateAnypointMqRegion,
+				Description:  "Region of the Anypoint MQ broker, e.g. us-east-1",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the client app used to connect to Anypoint MQ",
+			},
+			"client_secret": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "Secret of the client app used to connect to Anypoint MQ",
+			},
+			"secret_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Change to send the secrets again when they were rotated outside of Terraform",
+			},
+			"message_properties": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Properties added to the messages that are sent",
+			},
+		},
+	}
+
+	addSecretSourceSchema(mq, "client_secret")
+	return mq
+}
+
+// endpointObjectStorageSchema holds the attributes shared by the object storage configurations
+func endpointObjectStorageSchema(configName string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"config_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     configName,
+			Description: "name of the endpoint configuration",
+		},
+		"prefix": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Prefix of the objects that are read or written",
+		},
+		"archive_prefix": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Prefix to move read objects to",
+		},
+		"polling_frequency": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     1000,
+			Description: "Time to wait between checking for new objects",
+		},
+		"endpoint_url": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateEndpointUrl("http", "https"),
+			Description:  "URL of the storage service, to use a compatible service instead of the public cloud one",
+		},
+		"secret_version": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Change to send the secrets again when they were rotated outside of Terraform",
+		},
+	}
+}
+
+func endpointS3Schema() *schema.Resource {
+	s3 := &schema.Resource{Schema: endpointObjectStorageSchema("s3")}
+	s3.Schema["bucket"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validateS3BucketName,
+		Description:  "Name of the bucket",
+	}
+	s3.Schema["region"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Region of the bucket, e.g. us-east-1",
+	}
+	s3.Schema["access_key_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "ID of the access key used to access the bucket",
+	}
+	s3.Schema["secret_access_key"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		DiffSuppressFunc: suppressUnchangedSecret,
+		Description:      "Secret of the access key used to access the bucket",
+	}
+
+	addSecretSourceSchema(s3, "secret_access_key")
+	return s3
+}
+
+func endpointAzureBlobSchema() *schema.Resource {
+	blob := &schema.Resource{Schema: endpointObjectStorageSchema("azure_blob")}
+	blob.Schema["container"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validateAzureContainerName,
+		Description:  "Name of the container",
+	}
+	blob.Schema["account_name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validateAzureStorageAccountName,
+		Description:  "Name of the storage account of the container",
+	}
+	blob.Schema["account_key"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		DiffSuppressFunc: suppressUnchangedSecret,
+		Description:      "Access key of the storage account",
+	}
+
+	addSecretSourceSchema(blob, "account_key")
+	return blob
+}
+
+func endpointTlsContextSchema() *schema.Schema {
+	tlsContext := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether or not the connection is insecure",
+			},
+			"need_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether or not a certificate is needed",
+			},
+			"trust_store_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded CA certificates trusted when verifying the remote server",
+			},
+			"trust_store_certificate_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of muleb2b_certificate resources trusted when verifying the remote server",
+			},
+			"key_store_certificate_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded client certificate presented for mutual TLS",
+			},
+			"key_store_private_key_pem": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "PEM encoded private key of the client certificate presented for mutual TLS",
+			},
+			"key_store_certificate_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the muleb2b_certificate presented as the client certificate for mutual TLS",
+			},
+			"tls_versions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateOneOf(tlsVersions...)},
+				Description: "TLS protocol versions allowed for the connection: TLSv1.1, TLSv1.2, or TLSv1.3",
+			},
+			"cipher_suites": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Cipher suites allowed for the connection, in order of preference",
+			},
+		},
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem:     tlsContext,
+	}
+}
+
+func endpointProxySchema() *schema.Schema {
+	proxy := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "http",
+				ValidateFunc: validateOneOf("http", "socks5"),
+				Description:  "Type of the proxy: http or socks5",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Address of the proxy",
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntBetween(1, 65535),
+				Description:  "Port of the proxy",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Username to authenticate with the proxy",
+			},
+			"password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "Password to authenticate with the proxy",
+			},
+			"non_proxy_hosts": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Hosts that are connected to directly instead of through the proxy, e.g. *.internal.example.com",
+			},
+			"secret_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Change to send the password again when it was rotated outside of Terraform",
+			},
+		},
+	}
+
+	addSecretSourceSchema(proxy, "password")
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem:     proxy,
+	}
+}
+
+func endpointAuthModeSchema() *schema.Schema {
+	authMode := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateAuthModeType,
+				Description:  "Authentication Mode selected: none, basic, api_key, client_credentials, or oauth_token",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The username to use for authentication",
+			},
+			"password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "The password to use for authentication",
+			},
+			"http_header_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the HTTP header to use",
+			},
+			"api_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "API key to use for authentication",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the client to use for authentication",
+			},
+			"client_secret": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressUnchangedSecret,
+				Description:      "Secret to use in authentication",
+			},
+			"client_id_header": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Header to use for the client id",
+			},
+			"client_secret_header": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Header to use for the client secret",
+			},
+			"token_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTokenUrl,
+				Description:  "URL for the OAUTH token, must be an absolute https URL",
+			},
+			"scope": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Space separated scopes requested with the OAUTH token",
+			},
+			"audience": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Audience requested with the OAUTH token",
+			},
+			"resource": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Resource the OAUTH token is requested for",
+			},
+			"client_auth_style": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOneOf("body", "basic_header"),
+				Description:  "How the client credentials are sent in the token request: body or basic_header. Defaults to body",
+			},
+			"token_params": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateTokenParams,
+				Description:  "Additional parameters sent in the token request",
+			},
+			"secret_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Change to send the secrets again when they were rotated outside of Terraform",
+			},
 		},
 	}
+
+	addSecretSourceSchema(authMode, "password")
+	addSecretSourceSchema(authMode, "api_key")
+	addSecretSourceSchema(authMode, "client_secret")
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Required: true,
+		MaxItems: 1,
+		Elem:     authMode,
+	}
+}
+
+// addSecretSourceSchema adds the <secret>_file and <secret>_env alternatives of an inline secret to an auth mode
+func addSecretSourceSchema(authMode *schema.Resource, name string) {
+	authMode.Schema[name+"_file"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: fmt.Sprintf("Path of a file the %s is read from when the endpoint is created or updated. Conflicts with %s", name, name),
+	}
+	authMode.Schema[name+"_env"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: fmt.Sprintf("Name of an environment variable the %s is read from when the endpoint is created or updated. Conflicts with %s", name, name),
+	}
+}
+
+// endpointSftpAuthModeSchema adds public key authentication to the endpoint auth modes
+func endpointSftpAuthModeSchema() *schema.Schema {
+	s := endpointAuthModeSchema()
+	authMode := s.Elem.(*schema.Resource)
+
+	authMode.Schema["type"].ValidateFunc = validateSftpAuthModeType
+	authMode.Schema["type"].Description = "Authentication Mode selected: none, basic, api_key, client_credentials, oauth_token, or public_key"
+	authMode.Schema["private_key"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		DiffSuppressFunc: suppressUnchangedSecret,
+		Description:      "PEM encoded private key to use for public key authentication",
+	}
+	authMode.Schema["passphrase"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		DiffSuppressFunc: suppressUnchangedSecret,
+		Description:      "Passphrase of the private key",
+	}
+	addSecretSourceSchema(authMode, "private_key")
+	addSecretSourceSchema(authMode, "passphrase")
+	return s
+}
+
+func validateSftpAuthModeType(value interface{}, key string) (warnings []string, errors []error) {
+	if v, ok := value.(string); ok && v == "public_key" {
+		return warnings, errors
+	}
+	return validateAuthModeType(value, key)
+}
+
+func validateAuthModeType(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if v != "basic" && v != "none" && v != "api_key" && v != "client_credentials" && v != "oauth_token" {
+		errors = append(errors, fmt.Errorf("value of %q must be none, basic, api_key, client_credentials, or oauth_token", key))
+	}
+	return warnings, errors
+}
+
+func validateType(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if v != "sftp" && v != "http" && v != "as2" && v != "ftp" && v != "anypoint_mq" && v != "s3" && v != "azure_blob" {
+		errors = append(errors, fmt.Errorf("value of %q must be sftp, http, as2, ftp, anypoint_mq, s3, or azure_blob", key))
+	}
+	return warnings, errors
+}
+
+var anypointMqRegionRegexp = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-[0-9]$`)
+
+func validateAnypointMqRegion(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if !anypointMqRegionRegexp.MatchString(v) {
+		errors = append(errors, fmt.Errorf("value of %q must be an Anypoint MQ region, e.g. us-east-1", key))
+	}
+	return warnings, errors
+}
+
+// validateOneOf returns a validation function that ensures a string is one of the given values
+func validateOneOf(values ...string) schema.SchemaValidateFunc {
+	return func(value interface{}, key string) (warnings []string, errors []error) {
+		v, ok := value.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+		}
+
+		for _, allowed := range values {
+			if v == allowed {
+				return warnings, errors
+			}
+		}
+		errors = append(errors, fmt.Errorf("value of %q must be one of: %s", key, strings.Join(values, ", ")))
+		return warnings, errors
+	}
+}
+
+var s3BucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+var ipAddressRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// validateS3BucketName checks the S3 bucket naming rules
+func validateS3BucketName(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	switch {
+	case !s3BucketNameRegexp.MatchString(v):
+		errors = append(errors, fmt.Errorf("value of %q must be 3 to 63 lowercase letters, numbers, dots, or hyphens, and start and end with a letter or number", key))
+	case strings.Contains(v, ".."):
+		errors = append(errors, fmt.Errorf("value of %q must not contain two adjacent dots", key))
+	case ipAddressRegexp.MatchString(v):
+		errors = append(errors, fmt.Errorf("value of %q must not be formatted as an IP address", key))
+	case strings.HasPrefix(v, "xn--") || strings.HasSuffix(v, "-s3alias"):
+		errors = append(errors, fmt.Errorf("value of %q must not start with xn-- or end with -s3alias", key))
+	}
+	return warnings, errors
+}
+
+var azureContainerNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{1,61})[a-z0-9]$`)
+
+// validateAzureContainerName checks the Azure Blob Storage container naming rules
+func validateAzureContainerName(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if !azureContainerNameRegexp.MatchString(v) || strings.Contains(v, "--") {
+		errors = append(errors, fmt.Errorf("value of %q must be 3 to 63 lowercase letters, numbers, or single hyphens, and start and end with a letter or number", key))
+	}
+	return warnings, errors
 }
 
-func endpointAuthModeSchema() *schema.Schema {
-	return &schema.Schema{
-		Type:     schema.TypeSet,
-		Required: true,
-		Elem: &schema.Resource{
-			Schema: map[string]*schema.Schema{
-				"type": {
-					Type:         schema.TypeString,
-					Required:     true,
-					ValidateFunc: validateAuthModeType,
-					Description:  "Authentication Mode selected: none, basic, api_key, client_credentials, or oauth_token",
-				},
-				"username": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "The username to use for authentication",
-				},
-				"password": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "The password to use for authentication",
-				},
-				"http_header_name": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "The name of the HTTP header to use",
-				},
-				"api_key": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "API key to use for authentication",
-				},
-				"client_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "ID of the client to use for authentication",
-				},
-				"client_secret": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "Secret to use in authentication",
-				},
-				"client_id_header": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Header to use for the client id",
-				},
-				"client_secret_header": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Header to use for the client secret",
-				},
-				"token_url": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "URL for the OAUTH token",
-				},
-			},
-		},
+var azureStorageAccountNameRegexp = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
+
+func validateAzureStorageAccountName(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if !azureStorageAccountNameRegexp.MatchString(v) {
+		errors = append(errors, fmt.Errorf("value of %q must be 3 to 24 lowercase letters or numbers", key))
 	}
+	return warnings, errors
 }
 
-func validateAuthModeType(value interface{}, key string) (warnings []string, errors []error) {
+var httpHeaderNameRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// httpReservedHeaders are set from other attributes or by the HTTP client and can't be set in headers
+var httpReservedHeaders = []string{"Content-Type", "Content-Length", "Host", "Transfer-Encoding", "Connection"}
+
+func validateHttpHeaders(value interface{}, key string) (warnings []string, errors []error) {
+	headers, ok := value.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be map", key))
+		return warnings, errors
+	}
+
+	for name, v := range headers {
+		if !httpHeaderNameRegexp.MatchString(name) {
+			errors = append(errors, fmt.Errorf("%q contains an invalid header name: %q", key, name))
+			continue
+		}
+		for _, reserved := range httpReservedHeaders {
+			if strings.EqualFold(name, reserved) {
+				errors = append(errors, fmt.Errorf("%q can't set the %s header, it is set by the endpoint or content_type", key, reserved))
+			}
+		}
+		if s, ok := v.(string); ok && strings.ContainsAny(s, "\r\n") {
+			errors = append(errors, fmt.Errorf("%q header %s must not contain line breaks", key, name))
+		}
+	}
+	return warnings, errors
+}
+
+func validateQueryParams(value interface{}, key string) (warnings []string, errors []error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be map", key))
+		return warnings, errors
+	}
+
+	for name := range params {
+		if strings.TrimSpace(name) == "" {
+			errors = append(errors, fmt.Errorf("%q contains an empty parameter name", key))
+		}
+	}
+	return warnings, errors
+}
+
+func validateContentType(value interface{}, key string) (warnings []string, errors []error) {
 	v, ok := value.(string)
 	if !ok {
 		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
 	}
 
-	if v != "basic" && v != "none" && v != "api_key" && v != "client_credentials" && v != "oauth_token" {
-		errors = append(errors, fmt.Errorf("value of %q must be none, basic, api_key, client_credentials, or oauth_token", key))
+	if _, _, err := mime.ParseMediaType(v); err != nil {
+		errors = append(errors, fmt.Errorf("value of %q must be a media type, e.g. application/edi-x12: %s", key, err))
 	}
 	return warnings, errors
 }
 
-func validateType(value interface{}, key string) (warnings []string, errors []error) {
+func validateTokenUrl(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || !u.IsAbs() || u.Scheme != "https" || u.Host == "" {
+		errors = append(errors, fmt.Errorf("value of %q must be an absolute https URL, e.g. https://login.partner.com/oauth2/token", key))
+	}
+	return warnings, errors
+}
+
+// oauthReservedTokenParams are sent from other auth_mode attributes and can't be set in token_params
+var oauthReservedTokenParams = []string{"grant_type", "client_id", "client_secret", "scope", "audience", "resource"}
+
+func validateTokenParams(value interface{}, key string) (warnings []string, errors []error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be map", key))
+		return warnings, errors
+	}
+
+	for name := range params {
+		for _, reserved := range oauthReservedTokenParams {
+			if name == reserved {
+				errors = append(errors, fmt.Errorf("%q can't set %s, it is sent from the other auth_mode attributes", key, name))
+			}
+		}
+	}
+	return warnings, errors
+}
+
+// outputFileNameVariables are the variables that can be used in an output_file_name template
+var outputFileNameVariables = []string{"partner", "docType", "timestamp", "messageId", "uuid"}
+var outputFileNameVariableRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+func validateOutputFileName(value interface{}, key string) (warnings []string, errors []error) {
 	v, ok := value.(string)
 	if !ok {
 		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
 	}
 
-	if v != "sftp" && v != "http" {
-		errors = append(errors, fmt.Errorf("value of %q must be sftp or http", key))
+	if strings.Contains(v, "/") {
+		errors = append(errors, fmt.Errorf("value of %q must be a file name, not a path", key))
+	}
+	for _, match := range outputFileNameVariableRegexp.FindAllStringSubmatch(v, -1) {
+		known := false
+		for _, name := range outputFileNameVariables {
+			if match[1] == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			errors = append(errors, fmt.Errorf("%q uses unknown variable %s, supported variables are: %s", key, match[0], strings.Join(outputFileNameVariables, ", ")))
+		}
 	}
 	return warnings, errors
 }
 
+// validateIntBetween returns a validation function that ensures an int is between min and max, inclusive
+func validateIntBetween(min, max int) schema.SchemaValidateFunc {
+	return func(value interface{}, key string) (warnings []string, errors []error) {
+		v, ok := value.(int)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %q to be int", key))
+			return warnings, errors
+		}
+
+		if v < min || v > max {
+			errors = append(errors, fmt.Errorf("value of %q must be between %d and %d, got %d", key, min, max, v))
+		}
+		return warnings, errors
+	}
+}
+
 func validateRole(value interface{}, key string) (warnings []string, errors []error) {
 	v, ok := value.(string)
 	if !ok {
@@ -289,9 +1210,123 @@ func validateEndpointHttpProtocol(value interface{}, key string) (warnings []str
 	return warnings, errors
 }
 
+// endpointRoleTypes are the endpoint types each role can be used with
+var endpointRoleTypes = map[string][]string{
+	"send":        {"http", "sftp", "as2", "ftp", "anypoint_mq", "s3", "azure_blob"},
+	"receive":     {"http", "sftp", "as2", "ftp", "anypoint_mq", "s3", "azure_blob"},
+	"receive_ack": {"http", "sftp", "as2", "ftp", "s3", "azure_blob"},
+	"storage_api": {"http"},
+}
+
+// The configuration of an endpoint depends on its type, protocol and auth mode, which the schema can't express,
+// so the combinations are checked here instead of failing when the endpoint is created
+func resourceEndpointCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("role") {
+		return nil
+	}
+
+	role := d.Get("role").(string)
+	endType := d.Get("type").(string)
+
+	allowed := false
+	for _, t := range endpointRoleTypes[role] {
+		if t == endType {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("type %s can't be used with role %s, role %s supports: %s", endType, role, role, strings.Join(endpointRoleTypes[role], ", "))
+	}
+
+	if err := validateRetryPolicy(d.Get("retry_policy").(*schema.Set), role, endType); err != nil {
+		return err
+	}
+
+	for _, t := range []string{"http", "sftp", "as2", "ftp", "anypoint_mq", "s3", "azure_blob"} {
+		key := t + "_config"
+		if t != endType && len(d.Get(key).([]interface{})) > 0 {
+			return fmt.Errorf("%s can't be set when type is %s", key, endType)
+		}
+	}
+
+	key := endType + "_config"
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+	config := d.Get(key).([]interface{})
+	if len(config) == 0 {
+		return fmt.Errorf("%s is required when type is %s", key, endType)
+	}
+	cfg := config[0].(map[string]interface{})
+
+	switch endType {
+	case "http":
+		if err := validateEndpointUrlConfig(cfg, key, "protocol", "server_address", "server_port", "path"); err != nil {
+			return err
+		}
+		protocol := cfg["protocol"].(string)
+		if v := cfg["url"].(string); v != "" {
+			protocol, _, _, _, _ = parseEndpointUrl(v, "http", "https")
+		}
+		if protocol == "https" && len(cfg["tls_context"].([]interface{})) == 0 {
+			return fmt.Errorf("http_config.tls_context is required when http_config.protocol is https")
+		}
+		if err := validateHttpRequestConfig(cfg, role); err != nil {
+			return err
+		}
+	case "sftp":
+		if err := validateEndpointUrlConfig(cfg, key, "server_address", "server_port", "path"); err != nil {
+			return err
+		}
+		if err := validateSftpFileOptions(cfg, role); err != nil {
+			return err
+		}
+	case "ftp":
+		if ftpsMode := cfg["ftps_mode"].(string); ftpsMode != "none" && len(cfg["tls_context"].([]interface{})) == 0 {
+			return fmt.Errorf("ftp_config.tls_context is required when ftp_config.ftps_mode is %s", ftpsMode)
+		}
+	case "as2":
+		if cfg["protocol"].(string) == "https" && len(cfg["tls_context"].([]interface{})) == 0 {
+			return fmt.Errorf("as2_config.tls_context is required when as2_config.protocol is https")
+		}
+		if cfg["mdn_mode"].(string) == "async" && cfg["async_mdn_url"].(string) == "" {
+			return fmt.Errorf("as2_config.async_mdn_url is required when as2_config.mdn_mode is async")
+		}
+	case "anypoint_mq":
+		if err := validateSecretAttribute(cfg, key, "client_secret", true); err != nil {
+			return err
+		}
+	case "s3":
+		if err := validateSecretAttribute(cfg, key, "secret_access_key", true); err != nil {
+			return err
+		}
+	case "azure_blob":
+		if err := validateSecretAttribute(cfg, key, "account_key", true); err != nil {
+			return err
+		}
+	}
+
+	if authModes, ok := cfg["auth_mode"].([]interface{}); ok {
+		for _, raw := range authModes {
+			if err := validateAuthModeAttributes(raw.(map[string]interface{}), key+".auth_mode"); err != nil {
+				return err
+			}
+		}
+	}
+	if proxies, ok := cfg["proxy"].([]interface{}); ok {
+		for _, raw := range proxies {
+			if err := validateProxyAttributes(raw.(map[string]interface{}), key+".proxy"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func resourceEndpointCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 
 	name := d.Get("name").(string)
 	envId := d.Get("environment_id").(string) // Should be set on the resource
@@ -302,12 +1337,14 @@ func resourceEndpointCreate(d *schema.ResourceData, m interface{}) error {
 
 	partnerId := d.Get("partner_id").(string)
 
-	endpoint := muleb2b.Endpoint{
-		Name:          muleb2b.String(name),
-		EndpointRole:  muleb2b.String(strings.ToUpper(role)),
-		EnvironmentID: muleb2b.String(envId),
-		EndpointType:  muleb2b.String(endType),
-		PartnerID:     muleb2b.String(partnerId),
+	endpoint := apiEndpoint{
+		Endpoint: muleb2b.Endpoint{
+			Name:          muleb2b.String(name),
+			EndpointRole:  muleb2b.String(strings.ToUpper(role)),
+			EnvironmentID: muleb2b.String(envId),
+			EndpointType:  muleb2b.String(endType),
+			PartnerID:     muleb2b.String(partnerId),
+		},
 	}
 
 	desc, ok := d.Get("description").(string)
@@ -345,21 +1382,111 @@ func resourceEndpointCreate(d *schema.ResourceData, m interface{}) error {
 		} else {
 			return fmt.Errorf("http_config is required when type is set to http")
 		}
+	} else if endType == "ftp" {
+		cfg, ok := d.GetOk("ftp_config")
+		if ok {
+			endpointCfg, err := readFtpConfig(cfg)
+			if err != nil {
+				return err
+			}
+			endpoint.Config = endpointCfg
+		} else {
+			return fmt.Errorf("ftp_config is required when type is set to ftp")
+		}
+	} else if endType == "as2" {
+		cfg, ok := d.GetOk("as2_config")
+		if ok {
+			endpointCfg, err := readAs2Config(cfg)
+			if err != nil {
+				return err
+			}
+			endpoint.Config = endpointCfg
+		} else {
+			return fmt.Errorf("as2_config is required when type is set to as2")
+		}
+	} else if endType == "anypoint_mq" {
+		cfg, ok := d.GetOk("anypoint_mq_config")
+		if ok {
+			endpointCfg, err := readAnypointMqConfig(cfg)
+			if err != nil {
+				return err
+			}
+			endpoint.Config = endpointCfg
+		} else {
+			return fmt.Errorf("anypoint_mq_config is required when type is set to anypoint_mq")
+		}
+	} else if endType == "s3" {
+		cfg, ok := d.GetOk("s3_config")
+		if ok {
+			endpointCfg, err := readS3Config(cfg)
+			if err != nil {
+				return err
+			}
+			endpoint.Config = endpointCfg
+		} else {
+			return fmt.Errorf("s3_config is required when type is set to s3")
+		}
+	} else if endType == "azure_blob" {
+		cfg, ok := d.GetOk("azure_blob_config")
+		if ok {
+			endpointCfg, err := readAzureBlobConfig(cfg)
+			if err != nil {
+				return err
+			}
+			endpoint.Config = endpointCfg
+		} else {
+			return fmt.Errorf("azure_blob_config is required when type is set to azure_blob")
+		}
 	}
 
 	if *endpoint.EndpointType == "sftp" {
-		if err := d.Set("sftp_config", flattenSftpConfig(endpoint.Config, nil)); err != nil {
+		if err := d.Set("sftp_config", flattenSftpConfig(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
 			return err
 		}
 	} else if *endpoint.EndpointType == "http" {
-		if err := d.Set("http_config", flattenHttpConfig(endpoint.Config, nil)); err != nil {
+		if err := d.Set("http_config", flattenHttpConfig(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "ftp" {
+		if err := d.Set("ftp_config", flattenFtpConfig(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "as2" {
+		if err := d.Set("as2_config", flattenAs2Config(endpoint.Config)); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "anypoint_mq" {
+		if err := d.Set("anypoint_mq_config", flattenAnypointMqConfig(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "s3" {
+		if err := d.Set("s3_config", flattenS3Config(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "azure_blob" {
+		if err := d.Set("azure_blob_config", flattenAzureBlobConfig(endpoint.Config, sensitiveDataFromConfig(endpoint.Config))); err != nil {
 			return err
 		}
 	} else {
 		return fmt.Errorf("unsupported endpoint type: %s", *endpoint.EndpointType)
 	}
 
-	id, err := client.CreateEndpoint(endpoint)
+	// Secrets read from files or environment variables are resolved after the state is set, so they never reach it
+	if err := resolveSecretSources(endpoint.Config); err != nil {
+		return err
+	}
+	if *endpoint.EndpointType == "sftp" {
+		if err := validateSftpKeys(endpoint.Config); err != nil {
+			return err
+		}
+	}
+
+	endpoint.RetryPolicy = expandRetryPolicy(d.Get("retry_policy"))
+	if err := validateDeadLetterEndpoint(client, envId, "", endpoint.RetryPolicy); err != nil {
+		return err
+	}
+
+	id, err := createEndpoint(client, envId, &endpoint)
 
 	if err != nil {
 		return err
@@ -371,13 +1498,13 @@ func resourceEndpointCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceEndpointRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
 	client.SetEnvironment(envId)
 
-	endpoint, err := client.GetEndpoint(id)
+	endpoint, err := getEndpoint(client, envId, id)
 
 	if err != nil {
 		return err
@@ -395,38 +1522,28 @@ func resourceEndpointRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("description", *endpoint.Description)
 	}
 
-	// Retrieve sensitive data from state - this can be improved
-	var sensitive *sensitiveData = nil
+	// The configuration is read from the API so changes made outside of Terraform are detected. The API doesn't return
+	// secrets, they are kept from the state where they are only stored as a salted hash
+	var stateConfig *apiEndpointConfig = nil
 	if *endpoint.EndpointType == "sftp" {
-		endpoint.Config = expandSftpConfig(d.Get("sftp_config"))
-		if endpoint.Config.AuthMode.Password != nil {
-			sensitive = &sensitiveData{
-				password: endpoint.Config.AuthMode.Password,
-			}
-		} else if endpoint.Config.AuthMode.ClientSecret != nil {
-			sensitive = &sensitiveData{
-				clientSecret: endpoint.Config.AuthMode.ClientSecret,
-			}
-		} else if endpoint.Config.AuthMode.ApiKey != nil {
-			sensitive = &sensitiveData{
-				apiKey: endpoint.Config.AuthMode.ApiKey,
-			}
-		}
+		stateConfig = expandSftpConfig(d.Get("sftp_config"))
 	} else if *endpoint.EndpointType == "http" {
-		endpoint.Config = expandHttpConfig(d.Get("http_config"))
-		if endpoint.Config.AuthMode.Password != nil {
-			sensitive = &sensitiveData{
-				password: endpoint.Config.AuthMode.Password,
-			}
-		} else if endpoint.Config.AuthMode.ClientSecret != nil {
-			sensitive = &sensitiveData{
-				clientSecret: endpoint.Config.AuthMode.ClientSecret,
-			}
-		} else if endpoint.Config.AuthMode.ApiKey != nil {
-			sensitive = &sensitiveData{
-				apiKey: endpoint.Config.AuthMode.ApiKey,
-			}
-		}
+		stateConfig = expandHttpConfig(d.Get("http_config"))
+	} else if *endpoint.EndpointType == "ftp" {
+		stateConfig = expandFtpConfig(d.Get("ftp_config"))
+	} else if *endpoint.EndpointType == "as2" {
+		stateConfig = expandAs2Config(d.Get("as2_config"))
+	} else if *endpoint.EndpointType == "anypoint_mq" {
+		stateConfig = expandAnypointMqConfig(d.Get("anypoint_mq_config"))
+	} else if *endpoint.EndpointType == "s3" {
+		stateConfig = expandS3Config(d.Get("s3_config"))
+	} else if *endpoint.EndpointType == "azure_blob" {
+		stateConfig = expandAzureBlobConfig(d.Get("azure_blob_config"))
+	}
+	sensitive := sensitiveDataFromConfig(stateConfig)
+	endpoint.Config = mergeEndpointState(endpoint.Config, stateConfig)
+	if err = hashSecrets(endpoint.Config, sensitive); err != nil {
+		return err
 	}
 
 	if *endpoint.EndpointType == "sftp" {
@@ -437,27 +1554,57 @@ func resourceEndpointRead(d *schema.ResourceData, m interface{}) error {
 		if err = d.Set("http_config", flattenHttpConfig(endpoint.Config, sensitive)); err != nil {
 			return err
 		}
+	} else if *endpoint.EndpointType == "ftp" {
+		if err = d.Set("ftp_config", flattenFtpConfig(endpoint.Config, sensitive)); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "as2" {
+		if err = d.Set("as2_config", flattenAs2Config(endpoint.Config)); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "anypoint_mq" {
+		if err = d.Set("anypoint_mq_config", flattenAnypointMqConfig(endpoint.Config, sensitive)); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "s3" {
+		if err = d.Set("s3_config", flattenS3Config(endpoint.Config, sensitive)); err != nil {
+			return err
+		}
+	} else if *endpoint.EndpointType == "azure_blob" {
+		if err = d.Set("azure_blob_config", flattenAzureBlobConfig(endpoint.Config, sensitive)); err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("unsupported endpoint type: %s", *endpoint.EndpointType)
 	}
 
+	if err = d.Set("retry_policy", flattenRetryPolicy(endpoint.RetryPolicy)); err != nil {
+		return err
+	}
+	d.Set("url", endpointUrl(endpoint))
+
 	return nil
 }
 
 func resourceEndpointUpdate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
+
+	// Changed secrets are only hashed by the read after the update, keep the previous state if the update fails
+	d.Partial(true)
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
 
-	endpoint := muleb2b.Endpoint{
-		ID:            muleb2b.String(d.Id()),
-		Name:          muleb2b.String(d.Get("name").(string)),
-		EndpointRole:  muleb2b.String(strings.ToUpper(d.Get("role").(string))),
-		EndpointType:  muleb2b.String(d.Get("type").(string)),
-		EnvironmentID: muleb2b.String(d.Get("environment_id").(string)),
-		PartnerID:     muleb2b.String(d.Get("partner_id").(string)),
+	endpoint := apiEndpoint{
+		Endpoint: muleb2b.Endpoint{
+			ID:            muleb2b.String(d.Id()),
+			Name:          muleb2b.String(d.Get("name").(string)),
+			EndpointRole:  muleb2b.String(strings.ToUpper(d.Get("role").(string))),
+			EndpointType:  muleb2b.String(d.Get("type").(string)),
+			EnvironmentID: muleb2b.String(d.Get("environment_id").(string)),
+			PartnerID:     muleb2b.String(d.Get("partner_id").(string)),
+		},
 	}
 
 	desc, ok := d.Get("description").(string)
@@ -469,28 +1616,128 @@ func resourceEndpointUpdate(d *schema.ResourceData, m interface{}) error {
 		endpoint.PartnerCertificateID = muleb2b.String(v.(string))
 	}
 
+	// Secrets are only available in plaintext when their configuration block changed. The configuration is left out
+	// of the update otherwise, so the API keeps the current one with its secrets
+	if d.HasChange(d.Get("type").(string) + "_config") {
+		if *endpoint.EndpointType == "sftp" {
+			endpoint.Config = expandSftpConfig(d.Get("sftp_config"))
+		} else if *endpoint.EndpointType == "http" {
+			endpoint.Config = expandHttpConfig(d.Get("http_config"))
+		} else if *endpoint.EndpointType == "ftp" {
+			endpoint.Config = expandFtpConfig(d.Get("ftp_config"))
+		} else if *endpoint.EndpointType == "as2" {
+			endpoint.Config = expandAs2Config(d.Get("as2_config"))
+		} else if *endpoint.EndpointType == "anypoint_mq" {
+			endpoint.Config = expandAnypointMqConfig(d.Get("anypoint_mq_config"))
+		} else if *endpoint.EndpointType == "s3" {
+			endpoint.Config = expandS3Config(d.Get("s3_config"))
+		} else if *endpoint.EndpointType == "azure_blob" {
+			endpoint.Config = expandAzureBlobConfig(d.Get("azure_blob_config"))
+		}
+		if err := checkSecretsResolved(endpoint.Config); err != nil {
+			return err
+		}
+	}
+
+	if err := resolveSecretSources(endpoint.Config); err != nil {
+		return err
+	}
 	if *endpoint.EndpointType == "sftp" {
-		endpoint.Config = expandSftpConfig(d.Get("sftp_config"))
-	} else if *endpoint.EndpointType == "http" {
-		endpoint.Config = expandHttpConfig(d.Get("http_config"))
+		if err := validateSftpKeys(endpoint.Config); err != nil {
+			return err
+		}
+	}
+
+	endpoint.RetryPolicy = expandRetryPolicy(d.Get("retry_policy"))
+	if err := validateDeadLetterEndpoint(client, envId, d.Id(), endpoint.RetryPolicy); err != nil {
+		return err
 	}
 
-	err := client.UpdateEndpoint(endpoint)
+	err := updateEndpoint(client, envId, &endpoint)
 	if err != nil {
 		return err
 	}
+	d.Partial(false)
 
 	return resourceEndpointRead(d, m)
 }
 
 func resourceEndpointDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*muleb2b.Client)
+	client := m.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
 	client.SetEnvironment(envId)
 
-	err := client.DeleteEndpoint(id)
+	flows, err := findEndpointDocumentFlows(client, id)
+	if err != nil {
+		return err
+	}
+
+	if len(flows) > 0 {
+		if !d.Get("force_destroy").(bool) {
+			names := make([]string, len(flows))
+			for i, flow := range flows {
+				names[i] = *flow.Name
+			}
+			return fmt.Errorf("endpoint (%s) is referenced by the document flows %s, set force_destroy to detach it", id, strings.Join(names, ", "))
+		}
+
+		for _, flow := range flows {
+			err = loadDocumentFlowMappings(client, flow)
+			if err != nil {
+				return err
+			}
+			detachEndpointFromDocumentFlow(flow, id)
+			_, err = client.UpdateDocumentFlow(flow)
+			if err != nil {
+				return fmt.Errorf("failed to detach endpoint (%s) from document flow %s: %s", id, *flow.Name, err)
+			}
+		}
+	}
+
+	err = client.DeleteEndpoint(id)
 
 	return err
 }
+
+// endpointListBlocks are the blocks that were sets in version 0 of the schema. The elements of a set are compared by a
+// hash of their content, which changes with the random salt of every secret hash, so they are lists of one element
+// since version 1
+var endpointListBlocks = map[string]bool{
+	"http_config": true,
+	"sftp_config": true,
+	"ftp_config":  true,
+	"as2_config":  true,
+	"auth_mode":   true,
+	"tls_context": true,
+}
+
+func resourceEndpointMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		return migrateEndpointStateV0toV1(is), nil
+	default:
+		return is, fmt.Errorf("unexpected schema version: %d", v)
+	}
+}
+
+// migrateEndpointStateV0toV1 replaces the set hash of the elements of the endpoint blocks by the list index 0
+func migrateEndpointStateV0toV1(is *terraform.InstanceState) *terraform.InstanceState {
+	if is.Empty() {
+		return is
+	}
+
+	attributes := make(map[string]string, len(is.Attributes))
+	for k, v := range is.Attributes {
+		parts := strings.Split(k, ".")
+		for i := 1; i < len(parts); i++ {
+			if endpointListBlocks[parts[i-1]] && parts[i] != "#" {
+				parts[i] = "0"
+			}
+		}
+		attributes[strings.Join(parts, ".")] = v
+	}
+	is.Attributes = attributes
+	return is
+}
@@ -1,14 +1,22 @@
 package b2b
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"math/big"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAccMuleB2bEndpoint_http(t *testing.T) {
@@ -70,7 +78,6 @@ resource "muleb2b_endpoint" "test" {
 
 func testResourceEndpoint_InitialCheckHttp() resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		fmt.Sprintf("Checking Initial\n")
 		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
 		if resourceState == nil {
 			return fmt.Errorf("resource not found in state")
@@ -87,7 +94,7 @@ func testResourceEndpoint_InitialCheckHttp() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		_, err := client.GetEndpoint(id)
 		if err != nil {
 			return err
@@ -180,7 +187,6 @@ resource "muleb2b_endpoint" "test" {
 
 func testResourceEndpoint_UpdateCheckHttp() resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		fmt.Sprintf("Checking Update\n")
 		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
 		if resourceState == nil {
 			return fmt.Errorf("resource not found in state")
@@ -197,7 +203,7 @@ func testResourceEndpoint_UpdateCheckHttp() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		endpoint, err := client.GetEndpoint(id)
 		if err != nil {
 			return err
@@ -215,6 +221,609 @@ func testResourceEndpoint_UpdateCheckHttp() resource.TestCheckFunc {
 	}
 }
 
+func TestAccMuleB2bEndpoint_httpRequest(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigHttpRequest(envName, name, "POST"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "http_config.#", "1"),
+					testResourceEndpoint_CheckHttpRequest("POST"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigHttpRequest(envName, name, "PUT"),
+				Check:  testResourceEndpoint_CheckHttpRequest("PUT"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigHttpRequest(envName, name, method string) string {
+	return testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", fmt.Sprintf(`http_config {
+    url = "http://test.mytest.com/inbound"
+    method = "%s"
+    content_type = "application/edi-x12"
+    headers = {
+      "X-Partner-Id" = "%s"
+    }
+    query_params = {
+      source = "b2b"
+    }
+    auth_mode {
+      type = "none"
+    }
+  }`, method, name))
+}
+
+func testResourceEndpoint_CheckHttpRequest(method string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.Method == nil || *endpoint.Config.Method != method {
+			return fmt.Errorf("method did not update")
+		}
+		if endpoint.Config.Headers["X-Partner-Id"] != instanceState.Attributes["name"] {
+			return fmt.Errorf("X-Partner-Id header was not saved")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_httpOAuthToken(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigHttpOAuthToken(envName, name, "https://login.mytest.com/oauth2/token", "api://edi/.default"),
+				Check:  testResourceEndpoint_CheckOAuthScope("api://edi/.default"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigHttpOAuthToken(envName, name, "https://login.mytest.com/oauth2/token", "edi.read edi.write"),
+				Check:  testResourceEndpoint_CheckOAuthScope("edi.read edi.write"),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigHttpOAuthToken(envName, name, "http://login.mytest.com/oauth2/token", "edi.read"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("must be an absolute https URL"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigHttpOAuthToken(envName, name, tokenUrl, scope string) string {
+	return testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", fmt.Sprintf(`http_config {
+    url = "http://test.mytest.com/inbound"
+    auth_mode {
+      type = "oauth_token"
+      token_url = "%s"
+      client_id = "monkey"
+      client_secret = "business"
+      scope = "%s"
+      audience = "https://edi.mytest.com"
+      client_auth_style = "basic_header"
+      token_params = {
+        tenant = "mytest"
+      }
+    }
+  }`, tokenUrl, scope))
+}
+
+func testResourceEndpoint_CheckOAuthScope(scope string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.AuthMode == nil || endpoint.Config.AuthMode.Scope == nil || *endpoint.Config.AuthMode.Scope != scope {
+			return fmt.Errorf("scope did not update")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_retryPolicy(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigRetryPolicy(envName, name, 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "retry_policy.#", "1"),
+					testResourceEndpoint_CheckRetryPolicy(3),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigRetryPolicy(envName, name, 5),
+				Check:  testResourceEndpoint_CheckRetryPolicy(5),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigRetryPolicy(envName, name string, maxAttempts int) string {
+	return fmt.Sprintf(`%s
+
+resource "muleb2b_endpoint" "dead_letter" {
+  name = "%s-dlq"
+  role = "send"
+  type = "sftp"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  sftp_config {
+    url = "sftp://test.mytest.com/failed"
+    auth_mode {
+      type = "none"
+    }
+  }
+}`, testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", fmt.Sprintf(`http_config {
+    url = "http://test.mytest.com/inbound"
+    auth_mode {
+      type = "none"
+    }
+  }
+  retry_policy {
+    max_attempts = %d
+    backoff_interval = 10000
+    retryable_status_codes = [502, 503, 504]
+    dead_letter_endpoint_id = muleb2b_endpoint.dead_letter.id
+  }`, maxAttempts)), name)
+}
+
+func testResourceEndpoint_CheckRetryPolicy(maxAttempts int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.RetryPolicy == nil || endpoint.RetryPolicy.MaxAttempts == nil || *endpoint.RetryPolicy.MaxAttempts != maxAttempts {
+			return fmt.Errorf("retry_policy.max_attempts did not update")
+		}
+		if endpoint.RetryPolicy.DeadLetterEndpointId == nil || *endpoint.RetryPolicy.DeadLetterEndpointId != s.Modules[0].Resources["muleb2b_endpoint.dead_letter"].Primary.ID {
+			return fmt.Errorf("retry_policy.dead_letter_endpoint_id was not saved")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_httpProxy(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigHttpProxy(envName, name, 3128, `username = "monkey"
+      password = "business"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "http_config.#", "1"),
+					testResourceEndpoint_CheckProxyPort(3128),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigHttpProxy(envName, name, 8080, `username = "monkey"
+      password = "business"`),
+				Check: testResourceEndpoint_CheckProxyPort(8080),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigHttpProxy(envName, name, 8080, `password = "business"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("username is required"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigHttpProxy(envName, name string, port int, credentials string) string {
+	return testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", fmt.Sprintf(`http_config {
+    url = "http://test.mytest.com/inbound"
+    proxy {
+      host = "proxy.mytest.com"
+      port = %d
+      %s
+      non_proxy_hosts = ["*.internal.mytest.com"]
+    }
+    auth_mode {
+      type = "none"
+    }
+  }`, port, credentials))
+}
+
+func testResourceEndpoint_CheckProxyPort(port int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.Proxy == nil || endpoint.Config.Proxy.Port == nil || *endpoint.Config.Proxy.Port != port {
+			return fmt.Errorf("proxy port did not update")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_httpMutualTls(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceEndpoint_ConfigHttpMutualTls(envName, name, "not a certificate\n", certificate, privateKey, "TLSv1.2"),
+				ExpectError: regexp.MustCompile("trust_store_pem is not valid"),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigHttpMutualTls(envName, name, certificate, certificate, "not a key\n", "TLSv1.2"),
+				ExpectError: regexp.MustCompile("not a valid key pair"),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigHttpMutualTls(envName, name, certificate, certificate, privateKey, "SSLv3"),
+				ExpectError: regexp.MustCompile("tls_versions"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigHttpMutualTls(envName, name, certificate, certificate, privateKey, "TLSv1.2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "http"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "http_config.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigHttpMutualTls(envName, name, trustStore, certificate, privateKey, tlsVersion string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "http"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  http_config {
+    server_address = "test.mytest.com"
+    server_port = 443
+    path = "/"
+    protocol = "https"
+    auth_mode  {
+      type = "none"
+    }
+    tls_context {
+      need_certificate = true
+      trust_store_pem = <<EOF
+%sEOF
+      key_store_certificate_pem = <<EOF
+%sEOF
+      key_store_private_key_pem = <<EOF
+%sEOF
+      tls_versions = ["%s"]
+      cipher_suites = ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"]
+    }
+  }
+}`, envName, name, name, name, trustStore, certificate, privateKey, tlsVersion)
+}
+
+func TestAccMuleB2bEndpoint_httpSecretSources(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	os.Setenv("MULEB2B_TEST_ENDPOINT_PASSWORD", "business")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceEndpoint_ConfigHttpSecretSources(envName, name, `password = "business"`),
+				ExpectError: regexp.MustCompile("only one of http_config.auth_mode.password, http_config.auth_mode.password_file or http_config.auth_mode.password_env may be set"),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigHttpSecretSources(envName, name, `password_file = "/does/not/exist"`),
+				ExpectError: regexp.MustCompile("failed to read password_file"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigHttpSecretSources(envName, name, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "http"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "http_config.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigHttpSecretSources(envName, name, extraPassword string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "http"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  http_config {
+    server_address = "test.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode  {
+      type = "basic"
+      username = "monkey"
+      password_env = "MULEB2B_TEST_ENDPOINT_PASSWORD"
+      %s
+    }
+  }
+}`, envName, name, name, name, extraPassword)
+}
+
+func TestAccMuleB2bEndpoint_planValidation(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:             testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "sftp", ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile("sftp_config is required when type is sftp"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "storage_api", "sftp", `sftp_config {
+    server_address = "test.mytest.com"
+    server_port = 22
+    path = "banana"
+    auth_mode {
+      type = "none"
+    }
+  }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile("type sftp can't be used with role storage_api"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", `http_config {
+    server_address = "test.mytest.com"
+    server_port = 443
+    path = "/"
+    protocol = "https"
+    auth_mode {
+      type = "none"
+    }
+  }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile("http_config.tls_context is required when http_config.protocol is https"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", `http_config {
+    server_address = "test.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode {
+      type = "basic"
+      password = "business"
+    }
+  }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile("http_config.auth_mode.username is required when http_config.auth_mode.type is basic"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigPlanValidation(envName, name, role, endType, config string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "%s"
+  type = "%s"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  %s
+}`, envName, name, name, name, role, endType, config)
+}
+
+func TestAccMuleB2bEndpoint_url(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", `http_config {
+    url = "http://test.mytest.com:8080/inbound"
+    server_port = 8080
+    auth_mode {
+      type = "none"
+    }
+  }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile("http_config.url conflicts with http_config.server_port"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "http", `http_config {
+    url = "http://test.mytest.com:8080/inbound"
+    auth_mode {
+      type = "none"
+    }
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "url", "http://test.mytest.com:8080/inbound"),
+					testResourceEndpoint_CheckUrl("test.mytest.com", 8080, "/inbound"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "sftp", `sftp_config {
+    url = "sftp://test.mytest.com/in"
+    auth_mode {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "url", "sftp://test.mytest.com/in"),
+					testResourceEndpoint_CheckUrl("test.mytest.com", 22, "/in"),
+				),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_CheckUrl(address string, port int, path string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := client.GetEndpoint(resourceState.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.ServerAddress == nil || *endpoint.Config.ServerAddress != address {
+			return fmt.Errorf("server_address was not parsed from the url")
+		}
+		if endpoint.Config.ServerPort == nil || *endpoint.Config.ServerPort != port {
+			return fmt.Errorf("server_port was not parsed from the url")
+		}
+		if endpoint.Config.Path == nil || *endpoint.Config.Path != path {
+			return fmt.Errorf("path was not parsed from the url")
+		}
+		return nil
+	}
+}
+
 func TestAccMuleB2bEndpoint_sftp(t *testing.T) {
 	name := "accTest-" + acctest.RandString(5)
 	envName := os.Getenv("TEST_ENV_NAME")
@@ -224,18 +833,451 @@ func TestAccMuleB2bEndpoint_sftp(t *testing.T) {
 		CheckDestroy: testAccCheckExampleResourceDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testResourceEndpoint_InitialConfigSftp(envName, name),
-				Check:  testResourceEndpoint_InitialCheckSftp(),
+				Config: testResourceEndpoint_InitialConfigSftp(envName, name),
+				Check: resource.ComposeTestCheckFunc(
+					testResourceEndpoint_InitialCheckSftp(),
+					testResourceEndpoint_CheckSecretsHashed("business"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_UpdateConfigSftp(envName, name),
+				Check: resource.ComposeTestCheckFunc(
+					testResourceEndpoint_UpdateCheckSftp(),
+					testResourceEndpoint_CheckSecretsHashed("business"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_DescriptionConfigSftp(envName, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "description", "Nightly invoices"),
+					testResourceEndpoint_CheckCredentialKept("monkey"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccMuleB2bEndpoint_updateWithoutConfig ensures the API keeps the configuration of an endpoint, and its secrets,
+// when an update leaves it out. Updates of attributes outside of the configuration block rely on it
+func TestAccMuleB2bEndpoint_updateWithoutConfig(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_UpdateConfigSftp(envName, name),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						instanceState := s.Modules[0].Resources["muleb2b_endpoint.test"].Primary
+						envId := instanceState.Attributes["environment_id"]
+						client := testAccProvider.Meta().(*providerMeta).client
+						endpoint, err := getEndpoint(client, envId, instanceState.ID)
+						if err != nil {
+							return err
+						}
+						endpoint.Config = nil
+						endpoint.Description = muleb2b.String("updated without configuration")
+						return updateEndpoint(client, envId, endpoint)
+					},
+					testResourceEndpoint_CheckCredentialKept("monkey"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testResourceEndpoint_CheckCredentialKept ensures an update of a non-secret attribute did not remove the credentials
+// of the endpoint
+func testResourceEndpoint_CheckCredentialKept(username string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.AuthMode == nil || endpoint.Config.AuthMode.AuthType == nil ||
+			*endpoint.Config.AuthMode.AuthType != "BASIC" {
+			return fmt.Errorf("auth mode was removed by the update")
+		}
+		if endpoint.Config.AuthMode.Username == nil || *endpoint.Config.AuthMode.Username != username {
+			return fmt.Errorf("username was removed by the update")
+		}
+		if endpoint.Config.ServerPort == nil || *endpoint.Config.ServerPort != 22 {
+			return fmt.Errorf("configuration was changed by the update")
+		}
+		return nil
+	}
+}
+
+// testResourceEndpoint_CheckSecretsHashed ensures the secret is only stored in the state as its salted hash
+func testResourceEndpoint_CheckSecretsHashed(secret string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		found := false
+		for k, v := range resourceState.Primary.Attributes {
+			if v == secret {
+				return fmt.Errorf("%s is stored in plaintext", k)
+			}
+			if strings.HasSuffix(k, ".password") && secretMatchesHash(v, secret) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("hash of the password not found in state")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_sftpDrift(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	var envId, id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_InitialConfigSftp(envName, name),
+				Check: func(s *terraform.State) error {
+					resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+					if resourceState == nil || resourceState.Primary == nil {
+						return fmt.Errorf("resource not found in state")
+					}
+					envId, id = resourceState.Primary.Attributes["environment_id"], resourceState.Primary.ID
+					return nil
+				},
+			},
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*providerMeta).client
+					endpoint, err := getEndpoint(client, envId, id)
+					if err != nil {
+						t.Fatal(err)
+					}
+					endpoint.Config.ServerPort = muleb2b.Integer(2222)
+					if err := updateEndpoint(client, envId, endpoint); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testResourceEndpoint_InitialConfigSftp(envName, name),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_InitialConfigSftp(envName, name string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "sftp"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  sftp_config {
+    server_address = "test.mytest.com"
+    server_port = 80
+    path = "banana"
+    auth_mode  {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }
+}`, envName, name, name, name)
+}
+
+func testResourceEndpoint_InitialCheckSftp() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		id := instanceState.ID
+
+		if id == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		_, err := client.GetEndpoint(id)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func testResourceEndpoint_UpdateConfigSftp(envName, name string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "sftp"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  sftp_config {
+    server_address = "test.mytest.com"
+    server_port = 22
+    path = "banana"
+    auth_mode  {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }
+}`, envName, name, name, name)
+}
+
+func testResourceEndpoint_DescriptionConfigSftp(envName, name string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  description = "Nightly invoices"
+  role = "send"
+  type = "sftp"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  sftp_config {
+    server_address = "test.mytest.com"
+    server_port = 22
+    path = "banana"
+    auth_mode  {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }
+}`, envName, name, name, name)
+}
+
+func testResourceEndpoint_UpdateCheckSftp() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		id := instanceState.ID
+
+		if id == "" {
+			return fmt.Errorf("id is not set")
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := client.GetEndpoint(id)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.ServerPort == nil || *endpoint.Config.ServerPort != 22 {
+			return fmt.Errorf("server port did not update")
+		}
+
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_sftpFileOptions(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "receive", "sftp", `sftp_config {
+    url = "sftp://test.mytest.com/inbound"
+    working_directory = "/home/monkey"
+    file_name_pattern = "*.edi"
+    delete_after_read = true
+    min_file_age = 5000
+    auth_mode {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testResourceEndpoint_CheckSftpFileOptions("*.edi", ""),
+				),
 			},
 			{
-				Config: testResourceEndpoint_UpdateConfigSftp(envName, name),
-				Check:  testResourceEndpoint_UpdateCheckSftp(),
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "sftp", `sftp_config {
+    url = "sftp://test.mytest.com/outbound"
+    output_file_name = "$${partner}_$${docType}_$${timestamp}.edi"
+    write_mode = "temp_file_rename"
+    file_exists_action = "fail"
+    auth_mode {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "role", "send"),
+					testResourceEndpoint_CheckSftpFileOptions("", "${partner}_${docType}_${timestamp}.edi"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigPlanValidation(envName, name, "send", "sftp", `sftp_config {
+    url = "sftp://test.mytest.com/outbound"
+    output_file_name = "$${sender}.edi"
+    auth_mode {
+      type = "none"
+    }
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("uses unknown variable"),
 			},
 		},
 	})
 }
 
-func testResourceEndpoint_InitialConfigSftp(envName, name string) string {
+func testResourceEndpoint_CheckSftpFileOptions(fileNamePattern, outputFileName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil || resourceState.Primary == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, instanceState.Attributes["environment_id"], instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil {
+			return fmt.Errorf("endpoint has no configuration")
+		}
+		if fileNamePattern != "" && (endpoint.Config.FileNamePattern == nil || *endpoint.Config.FileNamePattern != fileNamePattern) {
+			return fmt.Errorf("file_name_pattern was not saved")
+		}
+		if outputFileName != "" && (endpoint.Config.OutputFileName == nil || *endpoint.Config.OutputFileName != outputFileName) {
+			return fmt.Errorf("output_file_name was not saved")
+		}
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_sftpPublicKey(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceEndpoint_ConfigSftpPublicKey(envName, name, "not a key", "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"),
+				ExpectError: regexp.MustCompile("private_key could not be parsed"),
+			},
+			{
+				Config:      testResourceEndpoint_ConfigSftpPublicKey(envName, name, string(privateKey), "not a fingerprint"),
+				ExpectError: regexp.MustCompile("host_key_fingerprint .* must be in the"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigSftpPublicKey(envName, name, string(privateKey), "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "sftp"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "sftp_config.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigSftpPublicKey(envName, name, privateKey, fingerprint string) string {
 	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
   name = "%s"
 }
@@ -262,20 +1304,81 @@ resource "muleb2b_endpoint" "test" {
   environment_id = data.muleb2b_environment.sbx.id
   sftp_config {
     server_address = "test.mytest.com"
-    server_port = 80
+    server_port = 22
     path = "banana"
+    host_key_fingerprint = "%s"
     auth_mode  {
-      type = "basic"
-      username = "monkey"
-      password = "business"
+      type = "public_key"
+      private_key = <<EOF
+%sEOF
     }
   }
-}`, envName, name, name, name)
+}`, envName, name, name, name, fingerprint, strings.TrimSuffix(privateKey, "\n")+"\n")
 }
 
-func testResourceEndpoint_InitialCheckSftp() resource.TestCheckFunc {
+func TestAccMuleB2bEndpoint_as2(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigAs2(envName, name, "sync"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "as2"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "as2_config.#", "1"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigAs2(envName, name, "async"),
+				Check:  testResourceEndpoint_CheckAs2("ASYNC"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigAs2(envName, name, mdnMode string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "as2"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  as2_config {
+    server_address = "as2.mytest.com"
+    server_port = 80
+    path = "/as2"
+    protocol = "http"
+    as2_from = "host-as2"
+    as2_to = "%s-id1"
+    mdn_mode = "%s"
+    async_mdn_url = "http://host.mytest.com/mdn"
+  }
+}`, envName, name, name, name, name, mdnMode)
+}
+
+func testResourceEndpoint_CheckAs2(mdnMode string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		fmt.Sprintf("Checking Initial\n")
 		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
 		if resourceState == nil {
 			return fmt.Errorf("resource not found in state")
@@ -286,23 +1389,49 @@ func testResourceEndpoint_InitialCheckSftp() resource.TestCheckFunc {
 			return fmt.Errorf("resource has no primary instance")
 		}
 
-		id := instanceState.ID
-
-		if id == "" {
-			return fmt.Errorf("id is not set")
-		}
-
-		client := testAccProvider.Meta().(*muleb2b.Client)
-		_, err := client.GetEndpoint(id)
+		envId := instanceState.Attributes["environment_id"]
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, envId, instanceState.ID)
 		if err != nil {
 			return err
 		}
 
+		if endpoint.Config == nil || endpoint.Config.MdnMode == nil || *endpoint.Config.MdnMode != mdnMode {
+			return fmt.Errorf("mdn_mode did not update")
+		}
+
 		return nil
 	}
 }
 
-func testResourceEndpoint_UpdateConfigSftp(envName, name string) string {
+func TestAccMuleB2bEndpoint_ftp(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigFtp(envName, name, 21, "none", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "ftp"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "ftp_config.#", "1"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigFtp(envName, name, 990, "implicit", `
+    tls_context {
+      insecure = false
+      need_certificate = false
+    }`),
+				Check: testResourceEndpoint_CheckFtp("IMPLICIT"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigFtp(envName, name string, port int, ftpsMode, tlsContext string) string {
 	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
   name = "%s"
 }
@@ -324,25 +1453,25 @@ resource "muleb2b_partner" "test" {
 resource "muleb2b_endpoint" "test" {
   name = "%s"
   role = "send"
-  type = "sftp"
+  type = "ftp"
   partner_id = muleb2b_partner.test.id
   environment_id = data.muleb2b_environment.sbx.id
-  sftp_config {
-    server_address = "test.mytest.com"
-    server_port = 22
+  ftp_config {
+    server_address = "ftp.mytest.com"
+    server_port = %d
     path = "banana"
+    ftps_mode = "%s"
     auth_mode  {
       type = "basic"
       username = "monkey"
       password = "business"
-    }
+    }%s
   }
-}`, envName, name, name, name)
+}`, envName, name, name, name, port, ftpsMode, tlsContext)
 }
 
-func testResourceEndpoint_UpdateCheckSftp() resource.TestCheckFunc {
+func testResourceEndpoint_CheckFtp(ftpsMode string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		fmt.Sprintf("Checking Update\n")
 		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
 		if resourceState == nil {
 			return fmt.Errorf("resource not found in state")
@@ -353,29 +1482,224 @@ func testResourceEndpoint_UpdateCheckSftp() resource.TestCheckFunc {
 			return fmt.Errorf("resource has no primary instance")
 		}
 
-		id := instanceState.ID
+		envId := instanceState.Attributes["environment_id"]
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, envId, instanceState.ID)
+		if err != nil {
+			return err
+		}
 
-		if id == "" {
-			return fmt.Errorf("id is not set")
+		if endpoint.Config == nil || endpoint.Config.FtpsMode == nil || *endpoint.Config.FtpsMode != ftpsMode {
+			return fmt.Errorf("ftps_mode did not update")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
-		endpoint, err := client.GetEndpoint(id)
+		return nil
+	}
+}
+
+func TestAccMuleB2bEndpoint_anypointMq(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigAnypointMq(envName, name, "orders-out"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "anypoint_mq"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "anypoint_mq_config.#", "1"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigAnypointMq(envName, name, "orders-out-v2"),
+				Check:  testResourceEndpoint_CheckAnypointMq("orders-out-v2"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigAnypointMq(envName, name, destination string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "as2" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "AS2"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.as2.id
+    value = "%s-id1"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "anypoint_mq"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  anypoint_mq_config {
+    destination = "%s"
+    region = "us-east-1"
+    client_id = "monkey"
+    client_secret = "business"
+    message_properties = {
+      partner = "%s"
+    }
+  }
+}`, envName, name, name, name, destination, name)
+}
+
+func testResourceEndpoint_CheckAnypointMq(destination string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		envId := instanceState.Attributes["environment_id"]
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, envId, instanceState.ID)
 		if err != nil {
 			return err
 		}
 
-		if endpoint.Config == nil || endpoint.Config.ServerPort == nil || *endpoint.Config.ServerPort != 22 {
-			return fmt.Errorf("server port did not update")
+		if endpoint.Config == nil || endpoint.Config.Destination == nil || *endpoint.Config.Destination != destination {
+			return fmt.Errorf("destination did not update")
 		}
 
 		return nil
 	}
 }
 
+func TestAccMuleB2bEndpoint_s3(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigObjectStorage(envName, name, "s3", `s3_config {
+    bucket = "b2b-inbound"
+    region = "us-east-1"
+    prefix = "orders/"
+    endpoint_url = "http://localhost:9000"
+    access_key_id = "minioadmin"
+    secret_access_key = "minioadmin"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "s3"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "s3_config.#", "1"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "url", "s3://b2b-inbound/orders/"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigObjectStorage(envName, name, "s3", `s3_config {
+    bucket = "b2b-inbound"
+    region = "us-east-1"
+    prefix = "orders/"
+    archive_prefix = "archive/"
+    endpoint_url = "http://localhost:9000"
+    access_key_id = "minioadmin"
+    secret_access_key = "minioadmin"
+  }`),
+				Check: testResourceEndpoint_CheckObjectStorage("archive/"),
+			},
+			{
+				Config: testResourceEndpoint_ConfigObjectStorage(envName, name, "s3", `s3_config {
+    bucket = "B2B..inbound"
+    region = "us-east-1"
+    access_key_id = "minioadmin"
+    secret_access_key = "minioadmin"
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("must be 3 to 63 lowercase letters"),
+			},
+		},
+	})
+}
+
+func TestAccMuleB2bEndpoint_azureBlob(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExampleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceEndpoint_ConfigObjectStorage(envName, name, "azure_blob", `azure_blob_config {
+    container = "b2b-inbound"
+    account_name = "devstoreaccount1"
+    endpoint_url = "http://127.0.0.1:10000/devstoreaccount1"
+    account_key = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "type", "azure_blob"),
+					resource.TestCheckResourceAttr("muleb2b_endpoint.test", "azure_blob_config.#", "1"),
+				),
+			},
+			{
+				Config: testResourceEndpoint_ConfigObjectStorage(envName, name, "azure_blob", `azure_blob_config {
+    container = "b2b-inbound"
+    account_name = "devstoreaccount1"
+    archive_prefix = "archive/"
+    endpoint_url = "http://127.0.0.1:10000/devstoreaccount1"
+    account_key = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+  }`),
+				Check: testResourceEndpoint_CheckObjectStorage("archive/"),
+			},
+		},
+	})
+}
+
+func testResourceEndpoint_ConfigObjectStorage(envName, name, endType, config string) string {
+	return testResourceEndpoint_ConfigPlanValidation(envName, name, "receive", endType, config)
+}
+
+func testResourceEndpoint_CheckObjectStorage(archivePrefix string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_endpoint.test"]
+		if resourceState == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		envId := instanceState.Attributes["environment_id"]
+		client := testAccProvider.Meta().(*providerMeta).client
+		endpoint, err := getEndpoint(client, envId, instanceState.ID)
+		if err != nil {
+			return err
+		}
+
+		if endpoint.Config == nil || endpoint.Config.ArchivePrefix == nil || *endpoint.Config.ArchivePrefix != archivePrefix {
+			return fmt.Errorf("archive_prefix did not update")
+		}
+
+		return nil
+	}
+}
 
 func testAccCheckExampleResourceDestroy(s *terraform.State) error {
-	cli := testAccProvider.Meta().(*muleb2b.Client)
+	cli := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "muleb2b_endpoint" {
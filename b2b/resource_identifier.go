@@ -10,9 +10,11 @@ func resourceIdentifier() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceIdentifierCreate,
 		Read:   resourceIdentifierRead,
-		Update: nil,
+		Update: resourceIdentifierUpdate,
 		Delete: resourceIdentifierDelete,
 
+		CustomizeDiff: resourceIdentifierCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"partner_id": {
 				Type:        schema.TypeString,
@@ -39,16 +41,65 @@ func resourceIdentifier() *schema.Resource {
 				Description: "The identifier value",
 			},
 			"status": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Status of the identifier",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ACTIVE",
+				ValidateFunc: validateIdentifierStatus,
+				Description:  "Status of the identifier, ACTIVE or INACTIVE",
 			},
 		},
 	}
 }
 
+// Identifiers route B2B messages, so they must match the format of their qualifier and be unique across
+// the partners in an environment
+func resourceIdentifierCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("identifier_type_id") && !d.HasChange("value") && !d.HasChange("partner_id") {
+		return nil
+	}
+	if !d.NewValueKnown("environment_id") || !d.NewValueKnown("identifier_type_id") || !d.NewValueKnown("value") {
+		return nil
+	}
+
+	// The identifier is added to its own partner, and the identifier being replaced belongs to the old partner and
+	// will be deleted
+	var partnerIds []string
+	if d.NewValueKnown("partner_id") {
+		partnerIds = append(partnerIds, d.Get("partner_id").(string))
+	}
+	if d.Id() != "" {
+		o, _ := d.GetChange("partner_id")
+		partnerIds = append(partnerIds, o.(string))
+	}
+
+	identifier := muleb2b.Identifier{
+		IdentifierTypeQualifierId: muleb2b.String(d.Get("identifier_type_id").(string)),
+		Value:                     muleb2b.String(d.Get("value").(string)),
+	}
+
+	client := meta.(*providerMeta).client
+	envId := d.Get("environment_id").(string)
+
+	if err := validateIdentifierValues(client, meta.(*providerMeta).identifierRules, envId, []*muleb2b.Identifier{&identifier}); err != nil {
+		return err
+	}
+	return validateIdentifiersUnique(meta.(*providerMeta), envId, []*muleb2b.Identifier{&identifier}, partnerIds...)
+}
+
+func validateIdentifierStatus(value interface{}, key string) (warnings []string, errors []error) {
+	v, ok := value.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+	}
+
+	if v != "ACTIVE" && v != "INACTIVE" {
+		errors = append(errors, fmt.Errorf("value of %q must be ACTIVE or INACTIVE", key))
+	}
+	return warnings, errors
+}
+
 func resourceIdentifierCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -57,7 +108,7 @@ func resourceIdentifierCreate(d *schema.ResourceData, meta interface{}) error {
 
 	identifier := muleb2b.Identifier{
 		IdentifierTypeQualifierId: muleb2b.String(d.Get("identifier_type_id").(string)),
-		Status:                    muleb2b.String("ACTIVE"),
+		Status:                    muleb2b.String(d.Get("status").(string)),
 		Value:                     muleb2b.String(d.Get("value").(string)),
 	}
 
@@ -79,7 +130,7 @@ func resourceIdentifierCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceIdentifierRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -98,8 +149,34 @@ func resourceIdentifierRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+func resourceIdentifierUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	envId := d.Get("environment_id").(string) // Should be set on the resource
+	client.SetEnvironment(envId)
+
+	partnerId := d.Get("partner_id").(string)
+
+	// Only the status can change in place, all other attributes force a new identifier
+	if d.HasChange("status") {
+		identifier := muleb2b.Identifier{
+			Id:                        muleb2b.String(d.Id()),
+			IdentifierTypeQualifierId: muleb2b.String(d.Get("identifier_type_id").(string)),
+			Status:                    muleb2b.String(d.Get("status").(string)),
+			Value:                     muleb2b.String(d.Get("value").(string)),
+		}
+
+		err := updatePartnerIdentifier(client, envId, partnerId, &identifier)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIdentifierRead(d, meta)
+}
+
 func resourceIdentifierDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
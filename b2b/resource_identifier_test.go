@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"os"
+	"regexp"
 	"testing"
 )
 
@@ -26,6 +27,17 @@ func TestAccMuleB2bIdentifier(t *testing.T) {
 				Config: testResourceIdentifier_UpdateConfig(envName, name, number),
 				Check:  testResourceIdentifier_UpdateCheck(number),
 			},
+			{
+				Config: testResourceIdentifier_InactiveConfig(envName, name, number),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_identifier.abc", "status", "INACTIVE"),
+					resource.TestCheckResourceAttr("muleb2b_identifier.abc", "value", fmt.Sprintf("%d3", number)),
+				),
+			},
+			{
+				Config:      testResourceIdentifier_DuplicateConfig(envName, name, number),
+				ExpectError: regexp.MustCompile("is already used by partner"),
+			},
 		},
 	})
 }
@@ -76,7 +88,7 @@ func testResourceIdentifier_InitialCheck(number int) resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		identifiers, err := client.ListPartnerIdentifiers(id)
 		if err != nil {
@@ -125,6 +137,48 @@ resource "muleb2b_identifier" "abc" {
 `, envName, name, number, number)
 }
 
+func testResourceIdentifier_InactiveConfig(envName, name string, number int) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d1"
+  }
+}
+
+resource "muleb2b_identifier" "abc" {
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier_type_id = data.muleb2b_identifier_type.duns.id
+  value = "%d3"
+  status = "INACTIVE"
+}
+`, envName, name, number, number)
+}
+
+func testResourceIdentifier_DuplicateConfig(envName, name string, number int) string {
+	return testResourceIdentifier_UpdateConfig(envName, name, number) + fmt.Sprintf(`
+resource "muleb2b_partner" "duplicate" {
+  name           = "%s-dup"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d1"
+  }
+}
+`, name, number)
+}
+
 func testResourceIdentifier_UpdateCheck(number int) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		resourceState := s.Modules[0].Resources["muleb2b_partner.test"]
@@ -143,7 +197,7 @@ func testResourceIdentifier_UpdateCheck(number int) resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		identifiers, err := client.ListPartnerIdentifiers(id)
 		if err != nil {
@@ -172,3 +226,52 @@ func testResourceIdentifierFindExpected(list []*muleb2b.Identifier, value string
 	}
 	return false
 }
+
+func TestAccMuleB2bIdentifier_invalidValue(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	envName := os.Getenv("TEST_ENV_NAME")
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceIdentifier_InvalidValueConfig(envName, name),
+				ExpectError: regexp.MustCompile("expected a 9 digit DUNS number"),
+			},
+		},
+	})
+}
+
+func testResourceIdentifier_InvalidValueConfig(envName, name string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+data "muleb2b_identifier_type" "isa" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "X12-ISA"
+  qualifier_code = "01"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%s"
+  }
+}
+
+resource "muleb2b_identifier" "abc" {
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier_type_id = data.muleb2b_identifier_type.isa.id
+  value = "12345"
+}
+`, envName, name, name)
+}
@@ -3,7 +3,9 @@ package b2b
 import (
 	"fmt"
 	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"github.com/hashicorp/terraform/configs/hcl2shim"
 	"github.com/hashicorp/terraform/helper/schema"
+	"strings"
 )
 
 func resourcePartner() *schema.Resource {
@@ -12,6 +14,11 @@ func resourcePartner() *schema.Resource {
 		Read:   resourcePartnerRead,
 		Update: resourcePartnerUpdate,
 		Delete: resourcePartnerDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePartnerImport,
+		},
+
+		CustomizeDiff: resourcePartnerCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -61,9 +68,11 @@ func resourcePartner() *schema.Resource {
 							Description: "The identifier value",
 						},
 						"status": {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: "Status of the identifier",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "ACTIVE",
+							ValidateFunc: validateIdentifierStatus,
+							Description:  "Status of the identifier, ACTIVE or INACTIVE",
 						},
 					},
 				},
@@ -287,8 +296,35 @@ func validateX12FailureAckType(value interface{}, key string) (warnings []string
 	return warnings, errors
 }
 
+// Identifiers route B2B messages, so they must match the format of their qualifier and be unique across
+// the partners in an environment
+func resourcePartnerCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("identifier") {
+		return nil
+	}
+	if !d.NewValueKnown("environment_id") || !d.NewValueKnown("identifier") {
+		return nil
+	}
+
+	var identifiers []*muleb2b.Identifier
+	for _, identifier := range expandIdentifiers(d.Get("identifier")) {
+		if *identifier.IdentifierTypeQualifierId == hcl2shim.UnknownVariableValue || *identifier.Value == hcl2shim.UnknownVariableValue {
+			continue
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	client := meta.(*providerMeta).client
+	envId := d.Get("environment_id").(string)
+
+	if err := validateIdentifierValues(client, meta.(*providerMeta).identifierRules, envId, identifiers); err != nil {
+		return err
+	}
+	return validateIdentifiersUnique(meta.(*providerMeta), envId, identifiers, d.Id())
+}
+
 func resourcePartnerCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -362,7 +398,7 @@ func resourcePartnerCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourcePartnerRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
@@ -379,8 +415,13 @@ func resourcePartnerRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("description", *(*partner).Description)
 	d.Set("website_url", *(*partner).WebsiteUrl)
 
-	// Get Identifiers
+	// Get Identifiers. Identifiers that are not in the state or configuration belong to muleb2b_identifier resources
+	// or were added outside of Terraform, so they are left out. An imported partner has no identifiers in the state
+	// yet, so every identifier is read
 	identifiers, err := client.ListPartnerIdentifiers(*(*partner).Id)
+	if managed := expandIdentifiers(d.Get("identifier")); len(managed) > 0 {
+		identifiers = filterManagedIdentifiers(identifiers, managed)
+	}
 	d.Set("identifier", flattenIdentifiers(identifiers))
 
 	// Get X12 Inbound Config
@@ -402,7 +443,7 @@ func resourcePartnerRead(d *schema.ResourceData, meta interface{}) error {
 
 func resourcePartnerUpdate(d *schema.ResourceData, meta interface{}) error {
 	d.Partial(true)
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource
 	client.SetEnvironment(envId)
@@ -448,6 +489,22 @@ func resourcePartnerUpdate(d *schema.ResourceData, meta interface{}) error {
 		del := identifierDifference(oldIdentifiers, newIdentifiers)
 		add := identifierDifference(newIdentifiers, oldIdentifiers)
 
+		// Identifiers that are kept may have had their status changed, added identifiers are created with theirs
+		for _, identifier := range identifierDifference(newIdentifiers, add) {
+			for _, current := range currentIdentifiers {
+				if identifier.QualifierIdAndValueEqual(current) {
+					if identifier.Status != nil && *identifier.Status != "" && (current.Status == nil || *identifier.Status != *current.Status) {
+						identifier.Id = current.Id
+						err := updatePartnerIdentifier(client, envId, d.Id(), identifier)
+						if err != nil {
+							return err
+						}
+					}
+					break
+				}
+			}
+		}
+
 		for _, identifier := range add {
 			if identifier.Status == nil || *identifier.Status == "" {
 				identifier.Status = muleb2b.String("ACTIVE")
@@ -593,7 +650,7 @@ func contactDifference(l1, l2 []*muleb2b.Contact) []*muleb2b.Contact {
 }
 
 func resourcePartnerDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*muleb2b.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 
 	envId := d.Get("environment_id").(string) // Should be set on the resource and in the state
@@ -611,3 +668,16 @@ func resourcePartnerDelete(d *schema.ResourceData, meta interface{}) error {
 
 	return nil
 }
+
+// resourcePartnerImport imports a partner from an ID of the form <environment_id>/<partner_id>
+func resourcePartnerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import id '%s', expected <environment_id>/<partner_id>", d.Id())
+	}
+
+	d.Set("environment_id", parts[0])
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,726 @@
+package b2b
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"github.com/hashicorp/terraform/helper/schema"
+	"sort"
+	"strings"
+)
+
+func resourcePartnerPromotion() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePartnerPromotionCreate,
+		Read:   resourcePartnerPromotionRead,
+		Update: resourcePartnerPromotionUpdate,
+		Delete: resourcePartnerPromotionDelete,
+
+		Schema: map[string]*schema.Schema{
+			"source_environment_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the environment the partner is promoted from",
+			},
+			"source_partner_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the partner to promote",
+			},
+			"target_environment_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the environment the partner is promoted to",
+			},
+			"certificate_bodies": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "PEM contents of the source partner's certificates, keyed by certificate name",
+			},
+			"endpoint_secrets": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Secrets of the source partner's endpoints, keyed by endpoint name and secret, e.g. partner-sftp/password",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that cause the promotion to be re-applied when changed",
+			},
+			"retain_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave the promoted partner in the target environment when this resource is destroyed",
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Promote into a partner with the same name that already exists in the target environment",
+			},
+			"adopted": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the partner already existed in the target environment. An adopted partner is never deleted by this resource",
+			},
+			"target_partner_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the partner in the target environment",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the promoted partner",
+			},
+			"identifier_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Target identifier IDs keyed by source identifier ID",
+			},
+			"certificate_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Target certificate IDs keyed by source certificate ID",
+			},
+			"endpoint_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Target endpoint IDs keyed by source endpoint ID",
+			},
+			"document_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Target document IDs keyed by source document ID",
+			},
+		},
+	}
+}
+
+// partnerSnapshot holds everything about a partner that is copied by a promotion
+type partnerSnapshot struct {
+	partner         *muleb2b.Partner
+	identifiers     []*muleb2b.Identifier
+	identifierTypes []*muleb2b.IdentifierType
+	contacts        []*muleb2b.Contact
+	address         *muleb2b.Address
+	x12             *muleb2b.X12
+	certificates    []*muleb2b.Certificate
+	endpoints       []*apiEndpoint
+	documents       []*muleb2b.Document
+}
+
+// partnerPromotionResult holds the IDs created or matched in the target environment
+type partnerPromotionResult struct {
+	partnerId      string
+	adopted        bool
+	identifierIds  map[string]string
+	certificateIds map[string]string
+	endpointIds    map[string]string
+	documentIds    map[string]string
+}
+
+func resourcePartnerPromotionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	result, err := promotePartner(d, client)
+	if result != nil && result.partnerId != "" {
+		// Track the partially promoted partner so it isn't orphaned if a later step fails
+		d.SetId(result.partnerId)
+		setPartnerPromotionResult(d, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	return resourcePartnerPromotionRead(d, meta)
+}
+
+func resourcePartnerPromotionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	envId := d.Get("target_environment_id").(string) // Should be set on the resource and in the state
+	client.SetEnvironment(envId)
+
+	partner, err := client.GetPartner(d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("target_partner_id", *partner.Id)
+	d.Set("name", *partner.Name)
+
+	return nil
+}
+
+func resourcePartnerPromotionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	// retain_on_destroy and the attributes forcing a new promotion don't need the promotion to be re-applied
+	if !d.HasChange("triggers") && !d.HasChange("certificate_bodies") && !d.HasChange("endpoint_secrets") {
+		return resourcePartnerPromotionRead(d, meta)
+	}
+
+	// Promotion is idempotent, so re-applying only creates what is missing and updates the rest, including the
+	// secrets of the endpoints that were promoted before
+	result, err := promotePartner(d, client)
+	if result != nil {
+		setPartnerPromotionResult(d, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	return resourcePartnerPromotionRead(d, meta)
+}
+
+func resourcePartnerPromotionDelete(d *schema.ResourceData, meta interface{}) error {
+	// Only a partner created by the promotion is deleted
+	if d.Get("retain_on_destroy").(bool) || d.Get("adopted").(bool) {
+		return nil
+	}
+
+	client := meta.(*providerMeta).client
+
+	envId := d.Get("target_environment_id").(string) // Should be set on the resource and in the state
+	client.SetEnvironment(envId)
+
+	// Identifiers, endpoints, certificates, documents, X12 configuration, contacts, and address are
+	// deleted with the partner
+	return client.DeletePartnerById(muleb2b.String(d.Id()))
+}
+
+func setPartnerPromotionResult(d *schema.ResourceData, result *partnerPromotionResult) {
+	d.Set("target_partner_id", result.partnerId)
+	d.Set("adopted", result.adopted)
+	d.Set("identifier_ids", result.identifierIds)
+	d.Set("certificate_ids", result.certificateIds)
+	d.Set("endpoint_ids", result.endpointIds)
+	d.Set("document_ids", result.documentIds)
+}
+
+// promotePartner copies the source partner into the target environment. Objects that already exist
+// in the target (matched by name, or by qualifier and value for identifiers) are reused so that the
+// promotion can be safely re-applied.
+func promotePartner(d *schema.ResourceData, client *muleb2b.Client) (*partnerPromotionResult, error) {
+	sourceEnvId := d.Get("source_environment_id").(string)
+	targetEnvId := d.Get("target_environment_id").(string)
+	sourcePartnerId := d.Get("source_partner_id").(string)
+
+	if sourceEnvId == targetEnvId {
+		return nil, fmt.Errorf("source_environment_id and target_environment_id must be different")
+	}
+
+	snapshot, err := readPartnerSnapshot(client, sourceEnvId, sourcePartnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API doesn't return endpoint secrets, fail before anything is created when one of them is not provided
+	secrets := d.Get("endpoint_secrets").(map[string]interface{})
+	var missingSecrets []string
+	for _, endpoint := range snapshot.endpoints {
+		for name := range requiredEndpointSecrets(*endpoint.EndpointType, endpoint.Config) {
+			if _, ok := secrets[endpointSecretKey(endpoint, name)]; !ok {
+				missingSecrets = append(missingSecrets, endpointSecretKey(endpoint, name))
+			}
+		}
+	}
+	if len(missingSecrets) > 0 {
+		sort.Strings(missingSecrets)
+		return nil, fmt.Errorf("endpoint secrets cannot be read from the source environment, add them to endpoint_secrets: %s", strings.Join(missingSecrets, ", "))
+	}
+
+	client.SetEnvironment(targetEnvId)
+
+	result := &partnerPromotionResult{
+		identifierIds:  map[string]string{},
+		certificateIds: map[string]string{},
+		endpointIds:    map[string]string{},
+		documentIds:    map[string]string{},
+	}
+
+	// Partner
+	partner := muleb2b.Partner{
+		Name:          snapshot.partner.Name,
+		EnvironmentId: muleb2b.String(targetEnvId),
+		Description:   snapshot.partner.Description,
+		WebsiteUrl:    snapshot.partner.WebsiteUrl,
+	}
+	existing, err := client.GetPartnerByName(*snapshot.partner.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Id != nil {
+		// The partner created by an earlier run of the promotion is reused, another partner with the same name
+		// is only taken over when asked to
+		if *existing.Id == d.Id() {
+			result.adopted = d.Get("adopted").(bool)
+		} else if d.Get("adopt_existing").(bool) {
+			result.adopted = true
+		} else {
+			return nil, fmt.Errorf("partner %s already exists in the target environment (%s), set adopt_existing to promote into it", *snapshot.partner.Name, *existing.Id)
+		}
+		partner.Id = existing.Id
+		err = client.UpdatePartner(&partner)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		id, err := client.CreatePartner(&partner)
+		if err != nil {
+			return nil, err
+		}
+		partner.Id = id
+	}
+	result.partnerId = *partner.Id
+
+	// Identifiers
+	qualifierIds, err := mapIdentifierTypeQualifiers(client, snapshot.identifierTypes)
+	if err != nil {
+		return result, err
+	}
+	for _, identifier := range snapshot.identifiers {
+		qualifierId, ok := qualifierIds[*identifier.IdentifierTypeQualifierId]
+		if !ok {
+			return result, fmt.Errorf("identifier type qualifier (%s) of identifier (%s) does not exist in the target environment", *identifier.IdentifierTypeQualifierId, *identifier.Value)
+		}
+		current, err := client.GetPartnerIdentifierByQualifierIdAndValue(result.partnerId, qualifierId, *identifier.Value)
+		if err != nil {
+			return result, err
+		}
+		if current == nil {
+			err = client.CreatePartnerIdentifier(result.partnerId, &muleb2b.Identifier{
+				IdentifierTypeQualifierId: muleb2b.String(qualifierId),
+				Status:                    identifier.Status,
+				Value:                     identifier.Value,
+			})
+			if err != nil {
+				return result, err
+			}
+			current, err = client.GetPartnerIdentifierByQualifierIdAndValue(result.partnerId, qualifierId, *identifier.Value)
+			if err != nil {
+				return result, err
+			} else if current == nil {
+				return result, fmt.Errorf("identifier (%s, %s) not created for partner (%s)", qualifierId, *identifier.Value, result.partnerId)
+			}
+		}
+		result.identifierIds[*identifier.Id] = *current.Id
+	}
+
+	// Certificates
+	bodies := d.Get("certificate_bodies").(map[string]interface{})
+	currentCertificates, err := client.ListPartnerCertificates(result.partnerId)
+	if err != nil {
+		return result, err
+	}
+	var missingBodies []string
+	for _, certificate := range snapshot.certificates {
+		if id := findCertificateIdByName(currentCertificates, *certificate.Name); id != "" {
+			result.certificateIds[*certificate.Id] = id
+			continue
+		}
+		body, ok := bodies[*certificate.Name]
+		if !ok {
+			missingBodies = append(missingBodies, *certificate.Name)
+			continue
+		}
+		certificateType := "PEM"
+		if certificate.CertificateType != nil && *certificate.CertificateType != "" {
+			certificateType = *certificate.CertificateType
+		}
+		id, err := client.CreatePartnerCertificate(result.partnerId, string(stripCR([]byte(body.(string)))), *certificate.Name, certificateType)
+		if err != nil {
+			return result, err
+		}
+		result.certificateIds[*certificate.Id] = *id
+	}
+	if len(missingBodies) > 0 {
+		return result, fmt.Errorf("certificate contents cannot be read from the source environment, add them to certificate_bodies: %s", strings.Join(missingBodies, ", "))
+	}
+
+	// Endpoints. An endpoint is promoted after the dead letter endpoint it uses
+	currentEndpoints, err := listPartnerEndpoints(client, result.partnerId)
+	if err != nil {
+		return result, err
+	}
+	sourceEndpointIds := map[string]bool{}
+	for _, source := range snapshot.endpoints {
+		sourceEndpointIds[*source.ID] = true
+	}
+	pending := snapshot.endpoints
+	for len(pending) > 0 {
+		var deferred []*apiEndpoint
+		for _, source := range pending {
+			if source.RetryPolicy != nil && source.RetryPolicy.DeadLetterEndpointId != nil {
+				deadLetterId := *source.RetryPolicy.DeadLetterEndpointId
+				if _, ok := result.endpointIds[deadLetterId]; !ok && sourceEndpointIds[deadLetterId] {
+					deferred = append(deferred, source)
+					continue
+				}
+			}
+
+			endpoint, err := promotedEndpoint(source, targetEnvId, result, secrets)
+			if err != nil {
+				return result, err
+			}
+
+			if id := findEndpointIdByName(currentEndpoints, *source.Name); id != "" {
+				endpoint.ID = muleb2b.String(id)
+				err = updateEndpoint(client, targetEnvId, endpoint)
+				if err != nil {
+					return result, err
+				}
+				result.endpointIds[*source.ID] = id
+			} else {
+				id, err := createEndpoint(client, targetEnvId, endpoint)
+				if err != nil {
+					return result, err
+				}
+				result.endpointIds[*source.ID] = *id
+			}
+		}
+		if len(deferred) == len(pending) {
+			return result, fmt.Errorf("dead letter endpoints of endpoint (%s) reference each other", *deferred[0].Name)
+		}
+		pending = deferred
+	}
+
+	// X12 Inbound Config
+	if snapshot.x12 != nil {
+		x12 := *snapshot.x12
+		x12.PartnerId = muleb2b.String(result.partnerId)
+		x12.EnvelopeHeaders = &muleb2b.X12EnvelopeHeaders{}
+		if x12.ParserSettings != nil && x12.ParserSettings.AckEndpointId != nil && *x12.ParserSettings.AckEndpointId != "" {
+			parserSettings := *x12.ParserSettings
+			ackEndpointId, ok := result.endpointIds[*parserSettings.AckEndpointId]
+			if !ok {
+				return result, fmt.Errorf("acknowledgement endpoint (%s) is not owned by the source partner and cannot be promoted", *parserSettings.AckEndpointId)
+			}
+			parserSettings.AckEndpointId = muleb2b.String(ackEndpointId)
+			x12.ParserSettings = &parserSettings
+		}
+		currentX12, err := findPartnerInboundX12Configuration(client, result.partnerId)
+		if err != nil {
+			return result, err
+		}
+		if currentX12 != nil {
+			x12.Id = currentX12.Id
+			err = client.UpdatePartnerX12Configuration(result.partnerId, &x12)
+		} else {
+			x12.Id = nil
+			err = client.CreatePartnerX12Configuration(result.partnerId, &x12)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// Contacts
+	if len(snapshot.contacts) > 0 {
+		currentContacts, err := client.GetPartnerContacts(result.partnerId)
+		if err != nil {
+			return result, err
+		}
+		var contacts []*muleb2b.Contact
+		for _, source := range snapshot.contacts {
+			contact := muleb2b.Contact{
+				Name:        source.Name,
+				Email:       source.Email,
+				Phone:       source.Phone,
+				ContactType: source.ContactType,
+			}
+			for _, current := range currentContacts {
+				if contactDifference([]*muleb2b.Contact{&contact}, []*muleb2b.Contact{current}) == nil {
+					contact.Id = current.Id
+					contact.Status = current.Status
+					break
+				}
+			}
+			contacts = append(contacts, &contact)
+		}
+		err = client.UpdatePartnerContacts(result.partnerId, contacts)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// Address
+	if snapshot.address != nil {
+		address := *snapshot.address
+		address.Id = nil
+		err = client.UpdatePartnerAddress(result.partnerId, &address)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// Documents
+	currentDocuments, err := client.ListDocuments(result.partnerId)
+	if err != nil {
+		return result, err
+	}
+	for _, source := range snapshot.documents {
+		doc := muleb2b.Document{
+			Name:              source.Name,
+			Standard:          source.Standard,
+			EdiDocumentTypeId: source.EdiDocumentTypeId,
+			SchemaType:        source.SchemaType,
+			SchemaContent:     source.SchemaContent,
+		}
+		if current := findDocumentByName(currentDocuments, *source.Name); current != nil {
+			// The schema of an existing document is updated, its custom schema is the one of the target environment
+			doc.Id = current.Id
+			doc.CustomSchemaId = current.CustomSchemaId
+			err = client.UpdateDocument(result.partnerId, &doc)
+			if err != nil {
+				return result, err
+			}
+			result.documentIds[*source.Id] = *current.Id
+			continue
+		}
+		id, err := client.CreateDocument(result.partnerId, &doc)
+		if err != nil {
+			return result, err
+		} else if id == nil {
+			return result, fmt.Errorf("nil id returned from document service")
+		}
+		result.documentIds[*source.Id] = *id
+	}
+
+	return result, nil
+}
+
+// endpointSecretKey returns the endpoint_secrets key of a secret of a source endpoint
+func endpointSecretKey(endpoint *apiEndpoint, name string) string {
+	return *endpoint.Name + "/" + name
+}
+
+// promotedEndpoint copies a source endpoint into the target partner. Certificate and dead letter endpoint IDs are
+// remapped to the promoted ones, and the secrets are taken from endpoint_secrets
+func promotedEndpoint(source *apiEndpoint, targetEnvId string, result *partnerPromotionResult, secrets map[string]interface{}) (*apiEndpoint, error) {
+	endpoint := &apiEndpoint{
+		Endpoint: muleb2b.Endpoint{
+			Name:          source.Name,
+			Description:   source.Description,
+			EnvironmentID: muleb2b.String(targetEnvId),
+			PartnerID:     muleb2b.String(result.partnerId),
+			EndpointRole:  source.EndpointRole,
+			EndpointType:  source.EndpointType,
+		},
+	}
+
+	// The source configuration is copied, so the snapshot can be promoted again
+	raw, err := json.Marshal(struct {
+		Config      *apiEndpointConfig `json:"config"`
+		RetryPolicy *apiRetryPolicy    `json:"retryPolicy,omitempty"`
+	}{source.Config, source.RetryPolicy})
+	if err == nil {
+		err = json.Unmarshal(raw, endpoint)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy endpoint (%s): %s", *source.Name, err)
+	}
+
+	remapCertificateId := func(id **string) error {
+		if *id == nil || **id == "" {
+			return nil
+		}
+		certificateId, ok := result.certificateIds[**id]
+		if !ok {
+			return fmt.Errorf("certificate (%s) used by endpoint (%s) was not promoted", **id, *source.Name)
+		}
+		*id = muleb2b.String(certificateId)
+		return nil
+	}
+
+	endpoint.PartnerCertificateID = source.PartnerCertificateID
+	if err := remapCertificateId(&endpoint.PartnerCertificateID); err != nil {
+		return nil, err
+	}
+
+	if cfg := endpoint.Config; cfg != nil {
+		if err := remapCertificateId(&cfg.SigningCertificateId); err != nil {
+			return nil, err
+		}
+		if err := remapCertificateId(&cfg.EncryptionCertificateId); err != nil {
+			return nil, err
+		}
+		if cfg.TlsContext != nil && cfg.TlsContext.TrustStore != nil {
+			for i := range cfg.TlsContext.TrustStore.CertificateIds {
+				id := &cfg.TlsContext.TrustStore.CertificateIds[i]
+				certificateId, ok := result.certificateIds[*id]
+				if !ok {
+					return nil, fmt.Errorf("certificate (%s) used by endpoint (%s) was not promoted", *id, *source.Name)
+				}
+				*id = certificateId
+			}
+		}
+		if cfg.TlsContext != nil && cfg.TlsContext.KeyStore != nil {
+			if err := remapCertificateId(&cfg.TlsContext.KeyStore.CertificateId); err != nil {
+				return nil, err
+			}
+		}
+
+		for name, secret := range requiredEndpointSecrets(*source.EndpointType, cfg) {
+			*secret = muleb2b.String(secrets[endpointSecretKey(source, name)].(string))
+		}
+		if passphrase, ok := secrets[endpointSecretKey(source, "passphrase")]; ok {
+			cfg.Passphrase = muleb2b.String(passphrase.(string))
+		}
+	}
+
+	if policy := endpoint.RetryPolicy; policy != nil && policy.DeadLetterEndpointId != nil {
+		deadLetterId, ok := result.endpointIds[*policy.DeadLetterEndpointId]
+		if !ok {
+			return nil, fmt.Errorf("dead letter endpoint (%s) of endpoint (%s) is not owned by the source partner and cannot be promoted", *policy.DeadLetterEndpointId, *source.Name)
+		}
+		policy.DeadLetterEndpointId = muleb2b.String(deadLetterId)
+	}
+
+	return endpoint, nil
+}
+
+// readPartnerSnapshot reads the partner and everything it owns from the source environment
+func readPartnerSnapshot(client *muleb2b.Client, envId, partnerId string) (*partnerSnapshot, error) {
+	client.SetEnvironment(envId)
+
+	var err error
+	snapshot := partnerSnapshot{}
+
+	snapshot.partner, err = client.GetPartner(partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.identifiers, err = client.ListPartnerIdentifiers(partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.identifierTypes, err = client.ListIdentifierTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.contacts, err = client.GetPartnerContacts(partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.address, err = findPartnerAddress(client, partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.x12, err = findPartnerInboundX12Configuration(client, partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.certificates, err = client.ListPartnerCertificates(partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := listPartnerEndpoints(client, partnerId)
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range endpoints {
+		// The list doesn't include the settings the muleb2b client does not model
+		endpoint, err := getEndpoint(client, envId, *summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint.Name == nil || endpoint.EndpointType == nil {
+			return nil, fmt.Errorf("endpoint (%s) has no name or type", *summary.ID)
+		}
+		snapshot.endpoints = append(snapshot.endpoints, endpoint)
+	}
+
+	documents, err := client.ListDocuments(partnerId)
+	if err != nil {
+		return nil, err
+	}
+	if documents != nil {
+		for _, document := range *documents {
+			// The list doesn't include custom schema contents
+			doc, err := client.GetDocumentById(partnerId, *document.Id)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.documents = append(snapshot.documents, doc)
+		}
+	}
+
+	return &snapshot, nil
+}
+
+// mapIdentifierTypeQualifiers maps the source environment's identifier type qualifier IDs to the IDs of
+// the qualifiers with the same type name and code in the client's current environment
+func mapIdentifierTypeQualifiers(client *muleb2b.Client, sourceTypes []*muleb2b.IdentifierType) (map[string]string, error) {
+	targetTypes, err := client.ListIdentifierTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	targetIds := map[string]string{}
+	for _, identifierType := range targetTypes {
+		for _, qualifier := range identifierType.Qualifiers {
+			targetIds[*identifierType.Name+"/"+*qualifier.Code] = *qualifier.Id
+		}
+	}
+
+	qualifierIds := map[string]string{}
+	for _, identifierType := range sourceTypes {
+		for _, qualifier := range identifierType.Qualifiers {
+			if id, ok := targetIds[*identifierType.Name+"/"+*qualifier.Code]; ok {
+				qualifierIds[*qualifier.Id] = id
+			}
+		}
+	}
+	return qualifierIds, nil
+}
+
+func findCertificateIdByName(certificates []*muleb2b.Certificate, name string) string {
+	for _, certificate := range certificates {
+		if certificate.Name != nil && *certificate.Name == name && certificate.Id != nil {
+			return *certificate.Id
+		}
+	}
+	return ""
+}
+
+func findEndpointIdByName(endpoints []muleb2b.Endpoint, name string) string {
+	for _, endpoint := range endpoints {
+		if endpoint.Name != nil && *endpoint.Name == name && endpoint.ID != nil {
+			return *endpoint.ID
+		}
+	}
+	return ""
+}
+
+func findDocumentByName(documents *[]muleb2b.Document, name string) *muleb2b.Document {
+	if documents != nil {
+		for i, document := range *documents {
+			if document.Name != nil && *document.Name == name && document.Id != nil {
+				return &(*documents)[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestAccMuleB2bPartnerPromotion(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	number := acctest.RandIntRange(100, 10000)
+	envName := os.Getenv("TEST_ENV_NAME")
+	targetEnvName := os.Getenv("TEST_TARGET_ENV_NAME")
+	if targetEnvName == "" {
+		t.Skip("TEST_TARGET_ENV_NAME must be set to test partner promotion")
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourcePartnerPromotion_InitialConfig(envName, targetEnvName, name, number, "1"),
+				Check:  testResourcePartnerPromotion_InitialCheck(name),
+			},
+			{
+				// Re-applying must reuse what was already promoted
+				Config: testResourcePartnerPromotion_InitialConfig(envName, targetEnvName, name, number, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("muleb2b_partner_promotion.test", "adopted", "false"),
+					testResourcePartnerPromotion_InitialCheck(name),
+				),
+			},
+			{
+				// A partner created by another promotion is not taken over
+				Config:      testResourcePartnerPromotion_AdoptConfig(envName, targetEnvName, name, number, false),
+				ExpectError: regexp.MustCompile("already exists in the target environment"),
+			},
+			{
+				Config: testResourcePartnerPromotion_AdoptConfig(envName, targetEnvName, name, number, true),
+				Check:  resource.TestCheckResourceAttr("muleb2b_partner_promotion.adopt", "adopted", "true"),
+			},
+		},
+	})
+}
+
+func testResourcePartnerPromotion_InitialConfig(envName, targetEnvName, name string, number int, trigger string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_environment" "target" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d"
+  }
+  contact {
+    name = "John Doe"
+    email = "test@test.com"
+    type = "business"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "receive_ack"
+  type = "http"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  http_config {
+    server_address = "test.mytest.com"
+    server_port = 80
+    path = "/"
+    protocol = "http"
+    auth_mode  {
+      type = "none"
+    }
+  }
+}
+
+resource "muleb2b_partner_promotion" "test" {
+  source_environment_id = data.muleb2b_environment.sbx.id
+  source_partner_id     = muleb2b_partner.test.id
+  target_environment_id = data.muleb2b_environment.target.id
+  triggers = {
+    run = "%s"
+  }
+  depends_on = [muleb2b_endpoint.test]
+}`, envName, targetEnvName, name, number, name, trigger)
+}
+
+func TestAccMuleB2bPartnerPromotion_endpointSecrets(t *testing.T) {
+	name := "accTest-" + acctest.RandString(5)
+	number := acctest.RandIntRange(100, 10000)
+	envName := os.Getenv("TEST_ENV_NAME")
+	targetEnvName := os.Getenv("TEST_TARGET_ENV_NAME")
+	if targetEnvName == "" {
+		t.Skip("TEST_TARGET_ENV_NAME must be set to test partner promotion")
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourcePartnerPromotion_EndpointSecretsConfig(envName, targetEnvName, name, number, ""),
+				ExpectError: regexp.MustCompile("add them to endpoint_secrets: " + name + "/password"),
+			},
+			{
+				Config: testResourcePartnerPromotion_EndpointSecretsConfig(envName, targetEnvName, name, number, fmt.Sprintf(`"%s/password" = "business"`, name)),
+				Check:  resource.TestCheckResourceAttr("muleb2b_partner_promotion.test", "endpoint_ids.%", "1"),
+			},
+			{
+				// A rotated secret re-applies the promotion, which updates the promoted endpoint
+				Config: testResourcePartnerPromotion_EndpointSecretsConfig(envName, targetEnvName, name, number, fmt.Sprintf(`"%s/password" = "rotated"`, name)),
+				Check:  resource.TestCheckResourceAttr("muleb2b_partner_promotion.test", "endpoint_ids.%", "1"),
+			},
+		},
+	})
+}
+
+func testResourcePartnerPromotion_EndpointSecretsConfig(envName, targetEnvName, name string, number int, secrets string) string {
+	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
+  name = "%s"
+}
+
+data "muleb2b_environment" "target" {
+  name = "%s"
+}
+
+data "muleb2b_identifier_type" "duns" {
+  environment_id = data.muleb2b_environment.sbx.id
+  name = "DUNS"
+}
+
+resource "muleb2b_partner" "test" {
+  name           = "%s"
+  environment_id = data.muleb2b_environment.sbx.id
+  identifier {
+    identifier_type_id = data.muleb2b_identifier_type.duns.id
+    value = "%d"
+  }
+}
+
+resource "muleb2b_endpoint" "test" {
+  name = "%s"
+  role = "send"
+  type = "sftp"
+  partner_id = muleb2b_partner.test.id
+  environment_id = data.muleb2b_environment.sbx.id
+  sftp_config {
+    url = "sftp://test.mytest.com/in"
+    auth_mode {
+      type = "basic"
+      username = "monkey"
+      password = "business"
+    }
+  }
+}
+
+resource "muleb2b_partner_promotion" "test" {
+  source_environment_id = data.muleb2b_environment.sbx.id
+  source_partner_id     = muleb2b_partner.test.id
+  target_environment_id = data.muleb2b_environment.target.id
+  endpoint_secrets = {
+    %s
+  }
+  depends_on = [muleb2b_endpoint.test]
+}`, envName, targetEnvName, name, number, name, secrets)
+}
+
+func testResourcePartnerPromotion_AdoptConfig(envName, targetEnvName, name string, number int, adopt bool) string {
+	return fmt.Sprintf(`%s
+
+resource "muleb2b_partner_promotion" "adopt" {
+  source_environment_id = data.muleb2b_environment.sbx.id
+  source_partner_id     = muleb2b_partner.test.id
+  target_environment_id = data.muleb2b_environment.target.id
+  adopt_existing        = %t
+  depends_on = [muleb2b_partner_promotion.test]
+}`, testResourcePartnerPromotion_InitialConfig(envName, targetEnvName, name, number, "2"), adopt)
+}
+
+func testResourcePartnerPromotion_InitialCheck(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["muleb2b_partner_promotion.test"]
+		if resourceState == nil {
+			return fmt.Errorf("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		if instanceState.Attributes["identifier_ids.%"] != "1" {
+			return fmt.Errorf("expected 1 promoted identifier, found %s", instanceState.Attributes["identifier_ids.%"])
+		}
+
+		if instanceState.Attributes["endpoint_ids.%"] != "1" {
+			return fmt.Errorf("expected 1 promoted endpoint, found %s", instanceState.Attributes["endpoint_ids.%"])
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		client.SetEnvironment(s.Modules[0].Resources["data.muleb2b_environment.target"].Primary.ID)
+
+		partners, err := client.ListPartners()
+		if err != nil {
+			return err
+		}
+
+		count := 0
+		for _, partner := range *partners {
+			if partner.Name != nil && *partner.Name == name {
+				count++
+			}
+		}
+		if count != 1 {
+			return fmt.Errorf("expected 1 partner named (%s) in the target environment, found %d", name, count)
+		}
+
+		endpoints, err := listPartnerEndpoints(client, instanceState.ID)
+		if err != nil {
+			return err
+		} else if len(endpoints) != 1 {
+			return fmt.Errorf("expected 1 endpoint in the target environment, found %d", len(endpoints))
+		}
+
+		return nil
+	}
+}
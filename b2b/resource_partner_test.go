@@ -2,7 +2,6 @@ package b2b
 
 import (
 	"fmt"
-	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
@@ -30,10 +29,24 @@ func TestAccMuleB2bPartner(t *testing.T) {
 				Config: testResourcePartner_UpdateConfig2(envName, name, number),
 				Check:  testResourcePartner_UpdateCheck2(),
 			},
+			{
+				ResourceName:      "muleb2b_partner.test",
+				ImportState:       true,
+				ImportStateIdFunc: testResourcePartner_ImportStateId,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
+func testResourcePartner_ImportStateId(s *terraform.State) (string, error) {
+	resourceState := s.Modules[0].Resources["muleb2b_partner.test"]
+	if resourceState == nil {
+		return "", fmt.Errorf("resource not found in state")
+	}
+	return fmt.Sprintf("%s/%s", resourceState.Primary.Attributes["environment_id"], resourceState.Primary.ID), nil
+}
+
 func testResourcePartner_InitialConfig(envName, name string, number int) string {
 	return fmt.Sprintf(`data "muleb2b_environment" "sbx" {
   name = "%s"
@@ -103,7 +116,7 @@ func testResourcePartner_InitialCheck() resource.TestCheckFunc {
 			return fmt.Errorf("id is not set")
 		}
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		client.SetEnvironment(envId)
 		partner, err := client.GetPartner(id)
 		if err != nil {
@@ -224,7 +237,7 @@ func testResourcePartner_UpdateCheck() resource.TestCheckFunc {
 
 		envId := s.Modules[0].Resources["data.muleb2b_environment.sbx"].Primary.ID
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		client.SetEnvironment(envId)
 		partner, err := client.GetPartner(id)
 		if err != nil {
@@ -342,7 +355,7 @@ func testResourcePartner_UpdateCheck2() resource.TestCheckFunc {
 
 		envId := s.Modules[0].Resources["data.muleb2b_environment.sbx"].Primary.ID
 
-		client := testAccProvider.Meta().(*muleb2b.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		client.SetEnvironment(envId)
 		partner, err := client.GetPartner(id)
 		if err != nil {
@@ -0,0 +1,82 @@
+package b2b
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// readSchemaPath reads the custom schema file at path and returns its base64 encoded contents and their SHA-256 hash
+func readSchemaPath(path string) (string, string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read schema_path: %s", err)
+	}
+	return base64.StdEncoding.EncodeToString(content), schemaHash(content), nil
+}
+
+func schemaHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeSchemaContent decodes a base64 encoded schema. Whitespace, missing padding and the URL alphabet are accepted,
+// content that is not base64 encoded is returned as is
+func decodeSchemaContent(content string) []byte {
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, content)
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if decoded, err := encoding.DecodeString(stripped); err == nil {
+			return decoded
+		}
+	}
+	return []byte(content)
+}
+
+// normalizeSchemaContent removes a byte order mark, carriage returns and trailing whitespace, which the API
+// doesn't preserve
+func normalizeSchemaContent(content []byte) string {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	lines := strings.Split(strings.Replace(string(content), "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// schemaContentsEquivalent reports whether two base64 encoded schemas have the same decoded contents. JSON schemas
+// are compared by value, other schemas after normalizing their whitespace
+func schemaContentsEquivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	decodedA := normalizeSchemaContent(decodeSchemaContent(a))
+	decodedB := normalizeSchemaContent(decodeSchemaContent(b))
+	if decodedA == decodedB {
+		return true
+	}
+
+	var jsonA, jsonB interface{}
+	if json.Unmarshal([]byte(decodedA), &jsonA) != nil || json.Unmarshal([]byte(decodedB), &jsonB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(jsonA, jsonB)
+}
+
+func suppressEquivalentSchema(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && new != "" && schemaContentsEquivalent(old, new)
+}
@@ -191,3 +191,87 @@ func expandDocumentFlowMapping(d interface{}) *muleb2b.Mapping {
 	}
 	return nil
 }
+
+// findEndpointDocumentFlows returns the document flows of the client's environment with a configuration that
+// references the endpoint. The summary list does not contain the configurations, so every flow is retrieved
+func findEndpointDocumentFlows(client *muleb2b.Client, endpointId string) ([]*muleb2b.DocumentFlow, error) {
+	summaries, err := client.ListDocumentFlows()
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []*muleb2b.DocumentFlow
+	if summaries == nil {
+		return flows, nil
+	}
+
+	for _, summary := range *summaries {
+		if summary.Id == nil {
+			continue
+		}
+		flow, err := client.GetDocumentFlowById(*summary.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve document flow (%s): %s", *summary.Id, err)
+		}
+		for _, config := range flow.Configurations {
+			if config != nil && len(documentFlowEndpointReferences(config, endpointId)) > 0 {
+				if flow.Name == nil {
+					flow.Name = flow.Id
+				}
+				flows = append(flows, flow)
+				break
+			}
+		}
+	}
+	return flows, nil
+}
+
+// documentFlowEndpointReferences returns the fields of a document flow configuration that reference the endpoint
+func documentFlowEndpointReferences(config *muleb2b.DocumentFlowConfiguration, endpointId string) []**string {
+	var references []**string
+	for _, ref := range []**string{
+		&config.ReceivingEndpointId,
+		&config.ReceivingAckEndpointId,
+		&config.TargetEndpointId,
+		&config.PreProcessingEndpointId,
+	} {
+		if *ref != nil && **ref == endpointId {
+			references = append(references, ref)
+		}
+	}
+	return references
+}
+
+// loadDocumentFlowMappings replaces the mapping references of a document flow with the complete mappings,
+// so an update of the flow sends its mappings back unchanged
+func loadDocumentFlowMappings(client *muleb2b.Client, flow *muleb2b.DocumentFlow) error {
+	for _, config := range flow.Configurations {
+		if config == nil {
+			continue
+		}
+		for i, ref := range config.DocumentMapping {
+			if ref == nil || ref.Id == nil {
+				continue
+			}
+			mapping, err := client.GetMappingById(*flow.Id, *ref.Id)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve mapping (%s) of document flow %s: %s", *ref.Id, *flow.Name, err)
+			}
+			config.DocumentMapping[i] = mapping
+		}
+	}
+	return nil
+}
+
+// detachEndpointFromDocumentFlow clears every reference to the endpoint in the configurations of a document flow.
+// The mappings are kept, so they must be loaded with loadDocumentFlowMappings first
+func detachEndpointFromDocumentFlow(flow *muleb2b.DocumentFlow, endpointId string) {
+	for _, config := range flow.Configurations {
+		if config == nil {
+			continue
+		}
+		for _, ref := range documentFlowEndpointReferences(config, endpointId) {
+			*ref = nil
+		}
+	}
+}
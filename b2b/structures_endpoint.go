@@ -1,440 +1,2544 @@
 package b2b
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 type sensitiveData struct {
-	password *string
-	clientSecret *string
-	apiKey *string
+	password        *string
+	clientSecret    *string
+	apiKey          *string
+	privateKey      *string
+	passphrase      *string
+	secretAccessKey *string
+	accountKey      *string
 }
 
-func readSftpConfig(data interface{}) (*muleb2b.EndpointConfig, error) {
-	config := data.(*schema.Set).List()
-	for _, raw := range config {
-		cfg, ok := raw.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("Failed to parse: %#v", raw)
-		}
+// secretHashPrefix marks a secret that has been replaced by its salted hash in the state
+const secretHashPrefix = "sha256:"
 
-		address := cfg["server_address"].(string)
-		port := cfg["server_port"].(int)
-		scwt := cfg["size_check_wait_time"].(int)
-		pollingFreq := cfg["polling_frequency"].(int)
-		path := cfg["path"].(string)
+// secretHashSaltSize is the number of random bytes each secret hash is salted with
+const secretHashSaltSize = 16
 
-		var archivePath *string = nil
-		if v, ok := cfg["archive_path"]; ok {
-			archivePath = muleb2b.String(v.(string))
+// endpointSecretFields are the endpoint attributes that are only stored in the state as a salted hash
+var endpointSecretFields = map[string]bool{
+	"password":                  true,
+	"api_key":                   true,
+	"client_secret":             true,
+	"private_key":               true,
+	"passphrase":                true,
+	"key_store_private_key_pem": true,
+	"secret_access_key":         true,
+	"account_key":               true,
+}
+
+// hashSecret returns the hash of a secret salted with random bytes, as sha256:<salt>:<hash>. Empty values and values that
+// are already hashed are returned as is
+func hashSecret(secret string) (string, error) {
+	if secret == "" || isSecretHash(secret) {
+		return secret, nil
+	}
+	salt := make([]byte, secretHashSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("failed to generate the salt of a secret hash: %s", err)
+	}
+	return saltedSecretHash(hex.EncodeToString(salt), secret), nil
+}
+
+// hashSecrets replaces the secrets of an endpoint configuration and of the sensitive data kept from the state by their
+// hash, so only hashes are written to the state
+func hashSecrets(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) error {
+	var err error
+	hash := func(secret **string) {
+		if err != nil || *secret == nil {
+			return
+		}
+		var hashed string
+		if hashed, err = hashSecret(**secret); err == nil {
+			*secret = &hashed
 		}
+	}
 
-		configName, ok := cfg["config_name"].(string)
-		if !ok || configName == "" {
-			configName = "sftp"
+	forEachSecret(endpointConfig, hash)
+	if sensitive != nil {
+		for _, secret := range []**string{&sensitive.password, &sensitive.clientSecret, &sensitive.apiKey, &sensitive.privateKey,
+			&sensitive.passphrase, &sensitive.secretAccessKey, &sensitive.accountKey} {
+			hash(secret)
 		}
+	}
+	return err
+}
 
-		amCfg, ok := cfg["auth_mode"]
-		if ok {
-			authMode, err := readAuthModeConfig(amCfg)
+func saltedSecretHash(salt, secret string) string {
+	sum := sha256.Sum256([]byte(salt + secret))
+	return secretHashPrefix + salt + ":" + hex.EncodeToString(sum[:])
+}
 
-			if err == nil {
-				endpointConfig := muleb2b.EndpointConfig{
-					MovedPath:         archivePath,
-					SizeCheckWaitTime: muleb2b.Integer(scwt),
-					PollingFrequency:  muleb2b.Integer(pollingFreq),
-					Path:              muleb2b.String(path),
-					ServerAddress:     muleb2b.String(address),
-					ServerPort:        muleb2b.Integer(port),
-					ConfigName:        muleb2b.String(configName),
-					AuthMode:          authMode,
-				}
-				return &endpointConfig, nil
-			} else {
-				return nil, err
-			}
+// splitSecretHash returns the salt and hash of a secret hash
+func splitSecretHash(value string) (string, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(value, secretHashPrefix), ":")
+	if !strings.HasPrefix(value, secretHashPrefix) || len(parts) != 2 {
+		return "", "", false
+	}
 
-		} else {
-			return nil, fmt.Errorf("auth_mode is required in sftp_config")
-		}
+	salt, hash := parts[0], parts[1]
+	if _, err := hex.DecodeString(salt); err != nil || len(salt) != 2*secretHashSaltSize {
+		return "", "", false
 	}
-	return nil, fmt.Errorf("sftp_config is required when type is sftp")
+	if _, err := hex.DecodeString(hash); err != nil || len(hash) != 2*sha256.Size {
+		return "", "", false
+	}
+	return salt, hash, true
 }
 
-func readHttpConfig(data interface{}) (*muleb2b.EndpointConfig, error) {
-	config := data.(*schema.Set).List()
-	for _, raw := range config {
-		cfg, ok := raw.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("Failed to parse: %#v", raw)
-		}
+func isSecretHash(value string) bool {
+	_, _, ok := splitSecretHash(value)
+	return ok
+}
 
-		address := cfg["server_address"].(string)
-		port := cfg["server_port"].(int)
-		path := cfg["path"].(string)
-		protocol := cfg["protocol"].(string)
-		responseTimeout := cfg["response_timeout"].(int)
-		idleTimeout := cfg["connection_idle_timeout"].(int)
+// secretMatchesHash reports whether a configured secret is the one a hash from the state was computed from
+func secretMatchesHash(hash, secret string) bool {
+	salt, _, ok := splitSecretHash(hash)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(saltedSecretHash(salt, secret)), []byte(hash)) == 1
+}
 
-		configName, ok := cfg["config_name"].(string)
-		if !ok || configName == "" {
-			configName = "http"
-		}
+// suppressUnchangedSecret hides the difference between the hash of a secret in the state and the configured secret
+// when the secret did not change. The API replaces the whole configuration of an endpoint, so every secret of a
+// configuration block is sent again whenever anything in the block changed, including another secret
+func suppressUnchangedSecret(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" || (old != new && !secretMatchesHash(old, new)) {
+		return false
+	}
 
-		amCfg, ok := cfg["auth_mode"]
-		var authMode *muleb2b.AuthMode = nil
-		var err error = nil
-		if ok {
-			authMode, err = readAuthModeConfig(amCfg)
-			if err != nil {
-				return nil, err
+	o, n := d.GetChange(strings.SplitN(k, ".", 2)[0])
+	return sameEndpointBlock(o, n)
+}
+
+// sameEndpointBlock reports whether a configuration block read from the state and the one read from the configuration
+// are the same. A secret is the same when the configured value is the one the hash in the state was computed from
+func sameEndpointBlock(old, new interface{}) bool {
+	switch o := old.(type) {
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok || len(o) != len(n) {
+			return false
+		}
+		for i := range o {
+			if !sameEndpointBlock(o[i], n[i]) {
+				return false
 			}
-		} else {
-			return nil, fmt.Errorf("auth_mode is required in http_config")
 		}
-
-		tlsCfg, ok := cfg["tls_context"]
-		var tlsContext *muleb2b.TlsContext = nil
-		if protocol == "https" {
-			if ok {
-				tlsContext, err = readTlsContextConfig(tlsCfg)
-				if err != nil {
-					return nil, err
+		return true
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok || len(o) != len(n) {
+			return false
+		}
+		for k, value := range o {
+			if endpointSecretFields[k] {
+				oldSecret, _ := value.(string)
+				newSecret, _ := n[k].(string)
+				if oldSecret != newSecret && !secretMatchesHash(oldSecret, newSecret) {
+					return false
 				}
-			} else {
-				return nil, fmt.Errorf("tls_context is required when protocol is https")
+			} else if !sameEndpointBlock(value, n[k]) {
+				return false
 			}
 		}
+		return true
+	case *schema.Set:
+		n, ok := new.(*schema.Set)
+		return ok && o.Equal(n)
+	default:
+		return reflect.DeepEqual(old, new)
+	}
+}
 
-		endpointConfig := muleb2b.EndpointConfig{
-			ConfigName:            muleb2b.String(configName),
-			ServerAddress:         muleb2b.String(address),
-			ServerPort:            muleb2b.Integer(port),
-			Path:                  muleb2b.String(path),
-			Protocol:              muleb2b.String(strings.ToUpper(protocol)),
-			ResponseTimeout:       muleb2b.Integer(responseTimeout),
-			ConnectionIdleTimeout: muleb2b.Integer(idleTimeout),
-			AuthMode:              authMode,
-			TlsContext:            tlsContext,
+// checkSecretsResolved ensures no secret of an endpoint configuration is only known by the hash read back from the
+// state. The API replaces the whole configuration, a secret that is left out is removed from the endpoint
+func checkSecretsResolved(endpointConfig *apiEndpointConfig) error {
+	unresolved := false
+	forEachSecret(endpointConfig, func(secret **string) {
+		if *secret != nil && isSecretHash(**secret) {
+			unresolved = true
 		}
+	})
+	if unresolved {
+		return fmt.Errorf("a secret of the endpoint configuration is only known by its hash, set it again in the configuration to update the endpoint")
+	}
+	return nil
+}
 
-		return &endpointConfig, nil
+// omitSecrets removes every secret of the endpoint configuration
+func omitSecrets(endpointConfig *apiEndpointConfig) {
+	forEachSecret(endpointConfig, func(secret **string) {
+		*secret = nil
+	})
+}
 
+func forEachSecret(endpointConfig *apiEndpointConfig, f func(secret **string)) {
+	if endpointConfig == nil {
+		return
+	}
+
+	if endpointConfig.AuthMode != nil {
+		f(&endpointConfig.AuthMode.Password)
+		f(&endpointConfig.AuthMode.ApiKey)
+		f(&endpointConfig.AuthMode.ClientSecret)
+	}
+	f(&endpointConfig.PrivateKey)
+	f(&endpointConfig.Passphrase)
+	f(&endpointConfig.SecretAccessKey)
+	f(&endpointConfig.AccountKey)
+	if endpointConfig.TlsContext != nil && endpointConfig.TlsContext.KeyStore != nil {
+		f(&endpointConfig.TlsContext.KeyStore.PrivateKeyPem)
+	}
+	if endpointConfig.Proxy != nil {
+		f(&endpointConfig.Proxy.Password)
 	}
-	return nil, fmt.Errorf("http_config is required when type is http")
 }
 
-func readAuthModeConfig(data interface{}) (*muleb2b.AuthMode, error) {
-	config := data.(*schema.Set).List()
-	for _, raw := range config {
-		cfg, ok := raw.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+// requiredEndpointSecrets returns the secrets an endpoint configuration needs, keyed by attribute name. The API doesn't
+// return secrets, so they have to be provided again when the configuration is copied to another endpoint
+func requiredEndpointSecrets(endpointType string, endpointConfig *apiEndpointConfig) map[string]**string {
+	secrets := map[string]**string{}
+	if endpointConfig == nil {
+		return secrets
+	}
+
+	if authMode := endpointConfig.AuthMode; authMode != nil && authMode.AuthType != nil {
+		for _, name := range authModeRequiredAttributes[strings.ToLower(*authMode.AuthType)] {
+			switch name {
+			case "password":
+				secrets[name] = &authMode.Password
+			case "api_key":
+				secrets[name] = &authMode.ApiKey
+			case "client_secret":
+				secrets[name] = &authMode.ClientSecret
+			case "private_key":
+				secrets[name] = &endpointConfig.PrivateKey
+			}
 		}
+	}
 
-		authType := cfg["type"].(string)
+	switch endpointType {
+	case "s3":
+		secrets["secret_access_key"] = &endpointConfig.SecretAccessKey
+	case "azure_blob":
+		secrets["account_key"] = &endpointConfig.AccountKey
+	}
 
-		switch authType {
-		case "none":
-			authMode := muleb2b.AuthMode{
-				AuthType: muleb2b.String("NONE"),
-			}
-			return &authMode, nil
+	if endpointConfig.Proxy != nil && endpointConfig.Proxy.Username != nil && *endpointConfig.Proxy.Username != "" {
+		secrets["proxy_password"] = &endpointConfig.Proxy.Password
+	}
+	if tlsContext := endpointConfig.TlsContext; tlsContext != nil && tlsContext.KeyStore != nil &&
+		(tlsContext.KeyStore.CertificatePem != nil || tlsContext.KeyStore.CertificateId != nil) {
+		secrets["key_store_private_key_pem"] = &tlsContext.KeyStore.PrivateKeyPem
+	}
+	return secrets
+}
 
-		case "basic":
-			user, uok := cfg["username"].(string)
-			password, pok := cfg["password"].(string)
-			if uok && pok {
-				authMode := muleb2b.AuthMode{
-					AuthType: muleb2b.String(strings.ToUpper(authType)),
-					Username: muleb2b.String(user),
-					Password: muleb2b.String(password),
+// readSecretVersion returns the secret_version of an auth_mode block
+func readSecretVersion(data interface{}) int {
+	if data != nil {
+		for _, raw := range data.([]interface{}) {
+			if cfg, ok := raw.(map[string]interface{}); ok {
+				if v, ok := cfg["secret_version"].(int); ok {
+					return v
 				}
-				return &authMode, nil
-			} else {
-				return nil, fmt.Errorf("username and password are required when auth_mode.type is basic")
 			}
+		}
+	}
+	return 0
+}
 
-		case "api_key":
-			apiKey, kok := cfg["api_key"].(string)
-			httpheaderName, hok := cfg["http_header_name"].(string)
-			if kok && hok {
-				authMode := muleb2b.AuthMode{
-					AuthType:       muleb2b.String(strings.ToUpper(authType)),
-					ApiKey:         muleb2b.String(apiKey),
-					HttpHeaderName: muleb2b.String(httpheaderName),
-				}
-				return &authMode, nil
-			} else {
-				return nil, fmt.Errorf("api_key and http_header_name are required when auth_mode.type is api_key")
-			}
+// endpointSecretSources are the secrets of an auth_mode block that can also be read from a file or an environment
+// variable with the <secret>_file and <secret>_env attributes
+var endpointSecretSources = []string{"password", "api_key", "client_secret", "private_key", "passphrase", "secret_access_key", "account_key"}
 
-		case "client_credentials":
-			clientId, idok := cfg["client_id"].(string)
-			clientSecret, sok := cfg["client_secret"].(string)
-			clientIdHeader, idhok := cfg["client_id_header"].(string)
-			clientSecretHeader, shok := cfg["client_secret_header"].(string)
-			if idhok && idok && sok && shok {
-				authMode := muleb2b.AuthMode{
-					AuthType:           muleb2b.String(strings.ToUpper(authType)),
-					ClientId:           muleb2b.String(clientId),
-					ClientSecret:       muleb2b.String(clientSecret),
-					ClientIdHeader:     muleb2b.String(clientIdHeader),
-					ClientSecretHeader: muleb2b.String(clientSecretHeader),
-				}
-				return &authMode, nil
-			} else {
-				return nil, fmt.Errorf("client_id, client_secret, client_id_header, and client_secret_header are required when auth_mode.type is client_credentials")
+// readSecretSources returns the <secret>_file and <secret>_env attributes that are set in an auth_mode block
+func readSecretSources(data interface{}) map[string]string {
+	sources := make(map[string]string)
+	if data != nil {
+		for _, raw := range data.([]interface{}) {
+			cfg, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
 			}
-
-		case "oauth_token":
-			tokenUrl, tok := cfg["token_url"].(string)
-			clientId, idok := cfg["client_id"].(string)
-			clientSecret, sok := cfg["client_secret"].(string)
-			if tok && idok && sok {
-				authMode := muleb2b.AuthMode{
-					AuthType:     muleb2b.String(strings.ToUpper(authType)),
-					TokenUrl:     muleb2b.String(tokenUrl),
-					ClientId:     muleb2b.String(clientId),
-					ClientSecret: muleb2b.String(clientSecret),
+			for _, name := range endpointSecretSources {
+				for _, suffix := range []string{"_file", "_env"} {
+					if v, ok := cfg[name+suffix].(string); ok && v != "" {
+						sources[name+suffix] = v
+					}
 				}
-				return &authMode, nil
-			} else {
-				return nil, fmt.Errorf("token_url, client_id, and client_secret are required when auth_mode.type is oauth_token")
 			}
-
-		default:
-			return nil, fmt.Errorf("invalid auth_mode.type specified")
 		}
 	}
-	return nil, nil
+	return sources
 }
 
-func readTlsContextConfig(data interface{}) (*muleb2b.TlsContext, error) {
-	config := data.(*schema.Set).List()
-	for _, raw := range config {
-		cfg, ok := raw.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("Failed to parse: %#v", raw)
-		}
+// authModeRequiredAttributes are the attributes each auth_mode type requires
+var authModeRequiredAttributes = map[string][]string{
+	"basic":              {"username", "password"},
+	"api_key":            {"api_key", "http_header_name"},
+	"client_credentials": {"client_id", "client_secret", "client_id_header", "client_secret_header"},
+	"oauth_token":        {"token_url", "client_id", "client_secret"},
+	"public_key":         {"private_key"},
+}
 
-		// Both values should be present since it was validated by
-		insecure := cfg["insecure"].(bool)
-		needCert := cfg["need_certificate"].(bool)
+// validateAuthModeAttributes ensures the attributes required by the type of an auth_mode block are set. Secrets may be
+// set inline or with their _file or _env alternative, but only one of them
+func validateAuthModeAttributes(cfg map[string]interface{}, path string) error {
+	for _, name := range endpointSecretSources {
+		if err := validateSecretAttribute(cfg, path, name, false); err != nil {
+			return err
+		}
+	}
 
-		tlsContext := muleb2b.TlsContext{
-			Insecure:        muleb2b.Boolean(insecure),
-			NeedCertificate: muleb2b.Boolean(needCert),
+	authType := cfg["type"].(string)
+	for _, name := range authModeRequiredAttributes[authType] {
+		if !isSecretAttributeSet(cfg, name) {
+			return fmt.Errorf("%s.%s is required when %s.type is %s", path, name, path, authType)
 		}
+	}
 
-		return &tlsContext, nil
+	if authType != "oauth_token" {
+		for _, name := range []string{"scope", "audience", "resource", "client_auth_style"} {
+			if v, ok := cfg[name].(string); ok && v != "" {
+				return fmt.Errorf("%s.%s can only be set when %s.type is oauth_token", path, name, path)
+			}
+		}
+		if v, ok := cfg["token_params"].(map[string]interface{}); ok && len(v) > 0 {
+			return fmt.Errorf("%s.token_params can only be set when %s.type is oauth_token", path, path)
+		}
 	}
-	return nil, nil
+	return nil
 }
 
-func flattenHttpConfig(endpointConfig *muleb2b.EndpointConfig, sensitive *sensitiveData) []interface{} {
-	m := make(map[string]interface{})
-
-	if endpointConfig != nil {
-		m["config_name"] = *endpointConfig.ConfigName
-		m["server_address"] = *endpointConfig.ServerAddress
-		m["server_port"] = *endpointConfig.ServerPort
-		m["path"] = *endpointConfig.Path
-		m["protocol"] = strings.ToLower(*endpointConfig.Protocol)
-		m["response_timeout"] = *endpointConfig.ResponseTimeout
-		m["connection_idle_timeout"] = *endpointConfig.ConnectionIdleTimeout
-		m["auth_mode"] = flattenAuthMode(endpointConfig.AuthMode, sensitive)
-		if strings.ToLower(*endpointConfig.Protocol) == "https" {
-			m["tls_context"] = flattenTlsContext(endpointConfig.TlsContext)
+// validateSecretAttribute ensures only one of a secret, its _file or its _env attribute is set, and that one of them
+// is set when the secret is required
+func validateSecretAttribute(cfg map[string]interface{}, path, name string, required bool) error {
+	count := 0
+	for _, k := range []string{name, name + "_file", name + "_env"} {
+		if v, ok := cfg[k].(string); ok && v != "" {
+			count++
 		}
 	}
-
-	return []interface{}{m}
+	if count > 1 {
+		return fmt.Errorf("only one of %s.%s, %s.%s_file or %s.%s_env may be set", path, name, path, name, path, name)
+	}
+	if required && count == 0 {
+		return fmt.Errorf("one of %s.%s, %s.%s_file or %s.%s_env is required", path, name, path, name, path, name)
+	}
+	return nil
 }
 
-func flattenSftpConfig(endpointConfig *muleb2b.EndpointConfig, sensitive *sensitiveData) []interface{}{
-	m := make(map[string]interface{})
-
-	if endpointConfig != nil {
-		m["config_name"] = *endpointConfig.ConfigName
-		m["server_address"] = *endpointConfig.ServerAddress
-		m["server_port"] = *endpointConfig.ServerPort
-		m["path"] = *endpointConfig.Path
-		if endpointConfig.MovedPath != nil {
-			m["archive_path"] = *endpointConfig.MovedPath
+// isSecretAttributeSet returns whether a secret is set inline or with its _file or _env attribute
+func isSecretAttributeSet(cfg map[string]interface{}, name string) bool {
+	for _, k := range []string{name, name + "_file", name + "_env"} {
+		if v, ok := cfg[k].(string); ok && v != "" {
+			return true
 		}
-		m["size_check_wait_time"] = *endpointConfig.SizeCheckWaitTime
-		m["polling_frequency"] = *endpointConfig.PollingFrequency
-		m["auth_mode"] = flattenAuthMode(endpointConfig.AuthMode, sensitive)
 	}
-
-	return []interface{}{m}
+	return false
 }
 
-func flattenAuthMode(authMode *muleb2b.AuthMode, sensitive *sensitiveData) []interface{} {
-	m := make(map[string]interface{})
+// readSecretSource returns the secret read from its file or environment variable. ok is false when neither is set
+func readSecretSource(sources map[string]string, name string) (secret string, ok bool, err error) {
+	file, fileOk := sources[name+"_file"]
+	env, envOk := sources[name+"_env"]
+	if fileOk && envOk {
+		return "", false, fmt.Errorf("only one of %s_file or %s_env may be set in auth_mode", name, name)
+	}
 
-	if authMode != nil {
-		m["type"] = strings.ToLower(*authMode.AuthType)
-		switch *authMode.AuthType {
-		case "BASIC":
-			m["username"] = *authMode.Username
-			if sensitive != nil && sensitive.password != nil {
-				m["password"] = *sensitive.password
-			}
-		case "API_KEY":
-			if sensitive != nil && sensitive.apiKey != nil {
-				m["api_key"] = *sensitive.apiKey
-			}
-			m["http_header_name"] = *authMode.HttpHeaderName
-		case "CLIENT_CREDENTIALS":
-			m["client_id"] = *authMode.ClientId
-			if sensitive != nil && sensitive.clientSecret != nil {
-				m["client_secret"] = *sensitive.clientSecret
-			}
-			m["client_id_header"] = *authMode.ClientIdHeader
-			m["client_secret_header"] = *authMode.ClientSecretHeader
-		case "OAUTH_TOKEN":
-			m["token_url"] = *authMode.TokenUrl
-			m["client_id"] = *authMode.ClientId
-			if sensitive != nil && sensitive.clientSecret != nil {
-				m["client_secret"] = *sensitive.clientSecret
-			}
+	if fileOk {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s_file: %s", name, err)
+		}
+		secret = string(content)
+		if name != "private_key" {
+			secret = strings.TrimRight(secret, "\r\n")
+		}
+		if secret == "" {
+			return "", false, fmt.Errorf("%s_file (%s) is empty", name, file)
 		}
+		return secret, true, nil
 	}
 
-	return []interface{}{m}
-}
-
-func flattenTlsContext(context *muleb2b.TlsContext) []interface{} {
-	m := make(map[string]interface{})
-	if context != nil {
-		m["insecure"] = *context.Insecure
-		m["need_certificate"] = *context.NeedCertificate
+	if envOk {
+		secret = os.Getenv(env)
+		if secret == "" {
+			return "", false, fmt.Errorf("environment variable %s referenced by %s_env is not set", env, name)
+		}
+		return secret, true, nil
 	}
-	return []interface{}{m}
+	return "", false, nil
 }
 
-func expandHttpConfig(d interface{}) *muleb2b.EndpointConfig {
-	if d != nil {
-		configList := d.(*schema.Set).List()
-		if len(configList) > 0 {
-			configData := configList[0].(map[string]interface{})
-			endpointConfig := muleb2b.EndpointConfig{
-				ConfigName:            muleb2b.String(configData["config_name"].(string)),
-				ServerAddress:         muleb2b.String(configData["server_address"].(string)),
-				ServerPort:            muleb2b.Integer(configData["server_port"].(int)),
-				Path:                  muleb2b.String(configData["path"].(string)),
-				Protocol:              muleb2b.String(strings.ToUpper(configData["protocol"].(string))),
-				ResponseTimeout:       muleb2b.Integer(configData["response_timeout"].(int)),
-				ConnectionIdleTimeout: muleb2b.Integer(configData["connection_idle_timeout"].(int)),
-			}
+// resolveSecretSources sets the secrets of the endpoint configuration that are read from files or environment variables.
+// It is only called right before the configuration is sent to the API, so the resolved secrets never reach the state
+func resolveSecretSources(endpointConfig *apiEndpointConfig) error {
+	if endpointConfig == nil {
+		return nil
+	}
+	if err := resolveProxySecretSources(endpointConfig.Proxy); err != nil {
+		return err
+	}
+	if len(endpointConfig.SecretSources) == 0 {
+		return nil
+	}
 
-			endpointConfig.AuthMode = expandAuthMode(configData["auth_mode"])
+	for _, name := range endpointSecretSources {
+		secret, ok, err := readSecretSource(endpointConfig.SecretSources, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
 
-			if strings.ToLower(*endpointConfig.Protocol) == "https" {
-				endpointConfig.TlsContext = expandTlsContext(configData["tls_context"])
-			}
+		var target **string
+		switch name {
+		case "password":
+			target = &endpointConfig.AuthMode.Password
+		case "api_key":
+			target = &endpointConfig.AuthMode.ApiKey
+		case "client_secret":
+			target = &endpointConfig.AuthMode.ClientSecret
+		case "private_key":
+			target = &endpointConfig.PrivateKey
+		case "passphrase":
+			target = &endpointConfig.Passphrase
+		case "secret_access_key":
+			target = &endpointConfig.SecretAccessKey
+		case "account_key":
+			target = &endpointConfig.AccountKey
+		}
 
-			return &endpointConfig
+		if *target != nil && **target != "" {
+			return fmt.Errorf("only one of %s, %s_file or %s_env may be set in auth_mode", name, name, name)
 		}
+		*target = muleb2b.String(secret)
 	}
 	return nil
 }
 
-func expandSftpConfig(d interface{}) *muleb2b.EndpointConfig {
-	if d != nil {
-		configList := d.(*schema.Set).List()
-		if len(configList) > 0 {
-			configData := configList[0].(map[string]interface{})
-			endpointConfig := muleb2b.EndpointConfig{
-				ConfigName:        muleb2b.String(configData["config_name"].(string)),
-				ServerAddress:     muleb2b.String(configData["server_address"].(string)),
-				ServerPort:        muleb2b.Integer(configData["server_port"].(int)),
-				Path:              muleb2b.String(configData["path"].(string)),
-				SizeCheckWaitTime: muleb2b.Integer(configData["size_check_wait_time"].(int)),
-				PollingFrequency:  muleb2b.Integer(configData["polling_frequency"].(int)),
-			}
-
-			if v, ok := configData["archive_path"]; ok {
-				endpointConfig.MovedPath = muleb2b.String(v.(string))
-			}
-
-			endpointConfig.AuthMode = expandAuthMode(configData["auth_mode"])
+// resolveProxySecretSources sets the proxy password when it is read from a file or an environment variable
+func resolveProxySecretSources(proxy *apiProxy) error {
+	if proxy == nil || len(proxy.SecretSources) == 0 {
+		return nil
+	}
 
-			return &endpointConfig
-		}
+	secret, ok, err := readSecretSource(proxy.SecretSources, "password")
+	if err != nil || !ok {
+		return err
+	}
+	if proxy.Password != nil && *proxy.Password != "" {
+		return fmt.Errorf("only one of password, password_file or password_env may be set in proxy")
 	}
+	proxy.Password = muleb2b.String(secret)
 	return nil
 }
 
-func expandAuthMode(d interface{}) *muleb2b.AuthMode {
-	if d != nil {
-		authList := d.(*schema.Set).List()
-		if len(authList) > 0 {
-			authData := authList[0].(map[string]interface{})
-			authType := strings.ToUpper(authData["type"].(string))
-			switch authType {
-			case "NONE":
-				authMode := muleb2b.AuthMode{
-					AuthType: muleb2b.String(authType),
-				}
-				return &authMode
+// flattenEndpointAuthMode flattens the auth mode of an endpoint configuration along with its secret_version
+func flattenEndpointAuthMode(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	authMode := flattenAuthMode(endpointConfig.AuthMode, sensitive)
+	if endpointConfig.AuthMode != nil {
+		am := authMode[0].(map[string]interface{})
+		am["secret_version"] = endpointConfig.SecretVersion
+		for k, v := range endpointConfig.SecretSources {
+			am[k] = v
+		}
+	}
+	return authMode
+}
 
-			case "BASIC":
-				authMode := muleb2b.AuthMode{
-					AuthType: muleb2b.String(authType),
-					Username: muleb2b.String(authData["username"].(string)),
-					Password: muleb2b.String(authData["password"].(string)),
-				}
-				return &authMode
+func readSftpConfig(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
 
-			case "API_KEY":
-				authMode := muleb2b.AuthMode{
-					AuthType:       muleb2b.String(authType),
-					ApiKey:         muleb2b.String(authData["api_key"].(string)),
-					HttpHeaderName: muleb2b.String(authData["http_header_name"].(string)),
-				}
-				return &authMode
+		address := cfg["server_address"].(string)
+		port := cfg["server_port"].(int)
+		scwt := cfg["size_check_wait_time"].(int)
+		pollingFreq := cfg["polling_frequency"].(int)
+		path := cfg["path"].(string)
 
-			case "CLIENT_CREDENTIALS":
-				authMode := muleb2b.AuthMode{
-					AuthType:           muleb2b.String(authType),
-					ClientId:           muleb2b.String(authData["client_id"].(string)),
-					ClientSecret:       muleb2b.String(authData["client_secret"].(string)),
-					ClientIdHeader:     muleb2b.String(authData["client_id_header"].(string)),
-					ClientSecretHeader: muleb2b.String(authData["client_secret_header"].(string)),
+		var archivePath *string = nil
+		if v, ok := cfg["archive_path"]; ok {
+			archivePath = muleb2b.String(v.(string))
+		}
+
+		configName, ok := cfg["config_name"].(string)
+		if !ok || configName == "" {
+			configName = "sftp"
+		}
+
+		amCfg, ok := cfg["auth_mode"]
+		if ok {
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					MovedPath:         archivePath,
+					SizeCheckWaitTime: muleb2b.Integer(scwt),
+					PollingFrequency:  muleb2b.Integer(pollingFreq),
+					Path:              muleb2b.String(path),
+					ServerAddress:     muleb2b.String(address),
+					ServerPort:        muleb2b.Integer(port),
+					ConfigName:        muleb2b.String(configName),
+				},
+			}
+
+			if err := readEndpointUrl(cfg, &endpointConfig, "sftp"); err != nil {
+				return nil, err
+			}
+			readSftpHostKeyConfig(cfg, &endpointConfig)
+			readSftpFileOptions(cfg, &endpointConfig)
+			endpointConfig.Proxy = expandProxy(cfg["proxy"])
+			endpointConfig.SecretVersion = readSecretVersion(amCfg)
+			endpointConfig.SecretSources = readSecretSources(amCfg)
+
+			if isSftpPublicKeyAuth(amCfg) {
+				if err := readSftpPublicKeyAuthConfig(amCfg, &endpointConfig); err != nil {
+					return nil, err
 				}
-				return &authMode
+				if err := validateSftpKeys(&endpointConfig); err != nil {
+					return nil, err
+				}
+				return &endpointConfig, nil
+			}
 
-			case "OAUTH_TOKEN":
-				authMode := muleb2b.AuthMode{
-					AuthType:     muleb2b.String(authType),
-					ClientId:     muleb2b.String(authData["client_id"].(string)),
-					ClientSecret: muleb2b.String(authData["client_secret"].(string)),
-					TokenUrl:     muleb2b.String(authData["token_url"].(string)),
+			authMode, err := readAuthModeConfig(amCfg)
+
+			if err == nil {
+				endpointConfig.AuthMode = authMode
+				if err := validateSftpKeys(&endpointConfig); err != nil {
+					return nil, err
 				}
-				return &authMode
+				return &endpointConfig, nil
+			} else {
+				return nil, err
 			}
+
+		} else {
+			return nil, fmt.Errorf("auth_mode is required in sftp_config")
+		}
+	}
+	return nil, fmt.Errorf("sftp_config is required when type is sftp")
+}
+
+func isSftpPublicKeyAuth(data interface{}) bool {
+	config := data.([]interface{})
+	for _, raw := range config {
+		if cfg, ok := raw.(map[string]interface{}); ok && cfg["type"].(string) == "public_key" {
+			return true
+		}
+	}
+	return false
+}
+
+func readSftpPublicKeyAuthConfig(data interface{}, endpointConfig *apiEndpointConfig) error {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		privateKey := cfg["private_key"].(string)
+		passphrase := cfg["passphrase"].(string)
+		if err := validateAuthModeAttributes(cfg, "auth_mode"); err != nil {
+			return err
+		}
+
+		endpointConfig.AuthMode = &apiAuthMode{AuthMode: muleb2b.AuthMode{
+			AuthType: muleb2b.String("PUBLIC_KEY"),
+		}}
+		if privateKey != "" {
+			endpointConfig.PrivateKey = muleb2b.String(privateKey)
+		}
+		if passphrase != "" {
+			endpointConfig.Passphrase = muleb2b.String(passphrase)
 		}
 	}
 	return nil
 }
 
-func expandTlsContext(d interface{}) *muleb2b.TlsContext {
+func readSftpHostKeyConfig(cfg map[string]interface{}, endpointConfig *apiEndpointConfig) {
+	if v := cfg["host_key_fingerprint"].(string); v != "" {
+		endpointConfig.HostKeyFingerprint = muleb2b.String(v)
+	}
+	if v := cfg["known_hosts"].(string); v != "" {
+		endpointConfig.KnownHosts = muleb2b.String(v)
+	}
+}
+
+func expandProxy(d interface{}) *apiProxy {
 	if d != nil {
-		tlsList := d.(*schema.Set).List()
-		if len(tlsList) > 0 {
-			tlsData := tlsList[0].(map[string]interface{})
-			tlsContext := muleb2b.TlsContext{
-				Insecure:        muleb2b.Boolean(tlsData["insecure"].(bool)),
-				NeedCertificate: muleb2b.Boolean(tlsData["need_certificate"].(bool)),
+		proxyList := d.([]interface{})
+		if len(proxyList) > 0 {
+			proxyData := proxyList[0].(map[string]interface{})
+			proxy := apiProxy{
+				Type:          muleb2b.String(strings.ToUpper(proxyData["type"].(string))),
+				Host:          muleb2b.String(proxyData["host"].(string)),
+				Port:          muleb2b.Integer(proxyData["port"].(int)),
+				NonProxyHosts: expandStringSet(proxyData["non_proxy_hosts"]),
+				SecretVersion: proxyData["secret_version"].(int),
+				SecretSources: readSecretSources(d),
+			}
+			if v := proxyData["username"].(string); v != "" {
+				proxy.Username = muleb2b.String(v)
+			}
+			if v := proxyData["password"].(string); v != "" {
+				proxy.Password = muleb2b.String(v)
+			}
+			return &proxy
+		}
+	}
+	return nil
+}
+
+func flattenProxy(proxy *apiProxy) []interface{} {
+	if proxy == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	if proxy.Type != nil {
+		m["type"] = strings.ToLower(*proxy.Type)
+	}
+	if proxy.Host != nil {
+		m["host"] = *proxy.Host
+	}
+	if proxy.Port != nil {
+		m["port"] = *proxy.Port
+	}
+	if proxy.Username != nil {
+		m["username"] = *proxy.Username
+	}
+	if proxy.Password != nil {
+		m["password"] = *proxy.Password
+	}
+	m["non_proxy_hosts"] = proxy.NonProxyHosts
+	m["secret_version"] = proxy.SecretVersion
+	for k, v := range proxy.SecretSources {
+		m[k] = v
+	}
+	return []interface{}{m}
+}
+
+// mergeProxyState combines the proxy returned by the API with the password and secret settings, which are only kept
+// in the state
+func mergeProxyState(apiProxy, stateProxy *apiProxy) *apiProxy {
+	if apiProxy == nil {
+		return nil
+	}
+
+	proxy := *apiProxy
+	proxy.Password = nil
+	if stateProxy != nil {
+		proxy.Password = stateProxy.Password
+		proxy.SecretVersion = stateProxy.SecretVersion
+		proxy.SecretSources = stateProxy.SecretSources
+	}
+	return &proxy
+}
+
+// mergeEndpointState combines the configuration returned by the API with the secrets and the attributes that are only
+// kept in the state: secret_version, the <secret>_file and <secret>_env attributes, and the url the server fields were
+// parsed from, as long as the server fields didn't change
+func mergeEndpointState(apiConfig, stateConfig *apiEndpointConfig) *apiEndpointConfig {
+	if apiConfig == nil {
+		return nil
+	}
+
+	endpointConfig := *apiConfig
+	if stateConfig == nil {
+		return &endpointConfig
+	}
+
+	endpointConfig.SecretVersion = stateConfig.SecretVersion
+	endpointConfig.SecretSources = stateConfig.SecretSources
+	if stateConfig.Url != nil && sameServer(apiConfig, stateConfig) {
+		endpointConfig.Url = stateConfig.Url
+	}
+	endpointConfig.Proxy = mergeProxyState(apiConfig.Proxy, stateConfig.Proxy)
+	if apiConfig.TlsContext != nil && apiConfig.TlsContext.KeyStore != nil &&
+		stateConfig.TlsContext != nil && stateConfig.TlsContext.KeyStore != nil {
+		tlsContext := *apiConfig.TlsContext
+		keyStore := *apiConfig.TlsContext.KeyStore
+		keyStore.PrivateKeyPem = stateConfig.TlsContext.KeyStore.PrivateKeyPem
+		tlsContext.KeyStore = &keyStore
+		endpointConfig.TlsContext = &tlsContext
+	}
+	return &endpointConfig
+}
+
+// sameServer reports whether the server fields returned by the API are the ones parsed from the url in the state
+func sameServer(apiConfig, stateConfig *apiEndpointConfig) bool {
+	if stateConfig.Protocol != nil && (apiConfig.Protocol == nil || !strings.EqualFold(*apiConfig.Protocol, *stateConfig.Protocol)) {
+		return false
+	}
+	return apiConfig.ServerAddress != nil && stateConfig.ServerAddress != nil && strings.EqualFold(*apiConfig.ServerAddress, *stateConfig.ServerAddress) &&
+		apiConfig.ServerPort != nil && stateConfig.ServerPort != nil && *apiConfig.ServerPort == *stateConfig.ServerPort &&
+		apiConfig.Path != nil && stateConfig.Path != nil && *apiConfig.Path == *stateConfig.Path
+}
+
+// validateProxyAttributes ensures the password of a proxy block is set once, and only along with a username
+func validateProxyAttributes(cfg map[string]interface{}, path string) error {
+	if err := validateSecretAttribute(cfg, path, "password", false); err != nil {
+		return err
+	}
+
+	hasUsername := cfg["username"].(string) != ""
+	hasPassword := isSecretAttributeSet(cfg, "password")
+	if hasUsername && !hasPassword {
+		return fmt.Errorf("%s.password is required when %s.username is set", path, path)
+	}
+	if hasPassword && !hasUsername {
+		return fmt.Errorf("%s.username is required when %s.password is set", path, path)
+	}
+	return nil
+}
+
+// readHttpRequestConfig reads the options that customize the requests sent by an http endpoint
+func readHttpRequestConfig(cfg map[string]interface{}, endpointConfig *apiEndpointConfig) {
+	if v, ok := cfg["method"].(string); ok && v != "" {
+		endpointConfig.Method = muleb2b.String(v)
+	}
+	if v, ok := cfg["content_type"].(string); ok && v != "" {
+		endpointConfig.ContentType = muleb2b.String(v)
+	}
+	if v := expandStringMap(cfg["headers"]); len(v) > 0 {
+		endpointConfig.Headers = v
+	}
+	if v := expandStringMap(cfg["query_params"]); len(v) > 0 {
+		endpointConfig.QueryParams = v
+	}
+}
+
+// validateHttpRequestConfig ensures the request options of an http_config block are only set on send endpoints and
+// don't set a header that the auth_mode sets
+func validateHttpRequestConfig(cfg map[string]interface{}, role string) error {
+	headers, _ := cfg["headers"].(map[string]interface{})
+	queryParams, _ := cfg["query_params"].(map[string]interface{})
+
+	if role != "send" {
+		switch {
+		case cfg["method"].(string) != "POST":
+			return fmt.Errorf("http_config.method can only be set when role is send")
+		case len(headers) > 0:
+			return fmt.Errorf("http_config.headers can only be set when role is send")
+		case cfg["content_type"].(string) != "":
+			return fmt.Errorf("http_config.content_type can only be set when role is send")
+		case len(queryParams) > 0:
+			return fmt.Errorf("http_config.query_params can only be set when role is send")
+		}
+	}
+
+	authModes, ok := cfg["auth_mode"].([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, raw := range authModes {
+		am := raw.(map[string]interface{})
+		if am["type"].(string) == "none" {
+			continue
+		}
+
+		authHeaders := []string{"Authorization"}
+		for _, k := range []string{"http_header_name", "client_id_header", "client_secret_header"} {
+			if v, ok := am[k].(string); ok && v != "" {
+				authHeaders = append(authHeaders, v)
+			}
+		}
+		for name := range headers {
+			for _, authHeader := range authHeaders {
+				if strings.EqualFold(name, authHeader) {
+					return fmt.Errorf("http_config.headers can't set the %s header, it is set by http_config.auth_mode", name)
+				}
 			}
-			return &tlsContext
 		}
 	}
 	return nil
 }
+
+// readSftpFileOptions reads the options that control how files are read from and written to the sftp server
+func readSftpFileOptions(cfg map[string]interface{}, endpointConfig *apiEndpointConfig) {
+	if v, ok := cfg["working_directory"].(string); ok && v != "" {
+		endpointConfig.WorkingDirectory = muleb2b.String(v)
+	}
+	if v, ok := cfg["file_name_pattern"].(string); ok && v != "" {
+		endpointConfig.FileNamePattern = muleb2b.String(v)
+	}
+	if v, ok := cfg["delete_after_read"].(bool); ok {
+		endpointConfig.DeleteAfterRead = muleb2b.Boolean(v)
+	}
+	if v, ok := cfg["min_file_age"].(int); ok && v > 0 {
+		fileAge := int64(v)
+		endpointConfig.FileAge = &fileAge
+	}
+	if v, ok := cfg["output_file_name"].(string); ok && v != "" {
+		endpointConfig.OutputFileName = muleb2b.String(v)
+	}
+	if v, ok := cfg["write_mode"].(string); ok && v != "" {
+		endpointConfig.WriteMode = muleb2b.String(strings.ToUpper(v))
+	}
+	if v, ok := cfg["file_exists_action"].(string); ok && v != "" {
+		endpointConfig.FileExistsAction = muleb2b.String(strings.ToUpper(v))
+	}
+}
+
+// flattenSftpFileOptions writes the file options of an sftp configuration into its flattened map
+func flattenSftpFileOptions(endpointConfig *apiEndpointConfig, m map[string]interface{}) {
+	if endpointConfig.WorkingDirectory != nil {
+		m["working_directory"] = *endpointConfig.WorkingDirectory
+	}
+	if endpointConfig.FileNamePattern != nil {
+		m["file_name_pattern"] = *endpointConfig.FileNamePattern
+	}
+	if endpointConfig.DeleteAfterRead != nil {
+		m["delete_after_read"] = *endpointConfig.DeleteAfterRead
+	}
+	if endpointConfig.FileAge != nil {
+		m["min_file_age"] = int(*endpointConfig.FileAge)
+	}
+	if endpointConfig.OutputFileName != nil {
+		m["output_file_name"] = *endpointConfig.OutputFileName
+	}
+	if endpointConfig.WriteMode != nil {
+		m["write_mode"] = strings.ToLower(*endpointConfig.WriteMode)
+	}
+	if endpointConfig.FileExistsAction != nil {
+		m["file_exists_action"] = strings.ToLower(*endpointConfig.FileExistsAction)
+	}
+}
+
+// validateSftpFileOptions ensures the file options of an sftp_config block fit its role. Options with a default
+// are only rejected when they are changed from it
+func validateSftpFileOptions(cfg map[string]interface{}, role string) error {
+	deleteAfterRead := cfg["delete_after_read"].(bool)
+	if deleteAfterRead && cfg["archive_path"].(string) != "" {
+		return fmt.Errorf("only one of sftp_config.delete_after_read or sftp_config.archive_path may be set")
+	}
+
+	var receiveOptions, sendOptions []string
+	if cfg["file_name_pattern"].(string) != "" {
+		receiveOptions = append(receiveOptions, "file_name_pattern")
+	}
+	if deleteAfterRead {
+		receiveOptions = append(receiveOptions, "delete_after_read")
+	}
+	if cfg["min_file_age"].(int) != 0 {
+		receiveOptions = append(receiveOptions, "min_file_age")
+	}
+	if cfg["output_file_name"].(string) != "" {
+		sendOptions = append(sendOptions, "output_file_name")
+	}
+	if cfg["write_mode"].(string) != "direct" {
+		sendOptions = append(sendOptions, "write_mode")
+	}
+	if cfg["file_exists_action"].(string) != "overwrite" {
+		sendOptions = append(sendOptions, "file_exists_action")
+	}
+
+	if role == "send" && len(receiveOptions) > 0 {
+		return fmt.Errorf("sftp_config.%s can't be set when role is send", receiveOptions[0])
+	}
+	if role != "send" && len(sendOptions) > 0 {
+		return fmt.Errorf("sftp_config.%s can only be set when role is send", sendOptions[0])
+	}
+	return nil
+}
+
+func readHttpConfig(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		address := cfg["server_address"].(string)
+		port := cfg["server_port"].(int)
+		path := cfg["path"].(string)
+		protocol := cfg["protocol"].(string)
+		if v := cfg["url"].(string); v != "" {
+			protocol, _, _, _, _ = parseEndpointUrl(v, "http", "https")
+		}
+		responseTimeout := cfg["response_timeout"].(int)
+		idleTimeout := cfg["connection_idle_timeout"].(int)
+
+		configName, ok := cfg["config_name"].(string)
+		if !ok || configName == "" {
+			configName = "http"
+		}
+
+		amCfg, ok := cfg["auth_mode"]
+		var authMode *apiAuthMode = nil
+		var err error = nil
+		if ok {
+			authMode, err = readAuthModeConfig(amCfg)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, fmt.Errorf("auth_mode is required in http_config")
+		}
+
+		tlsCfg, ok := cfg["tls_context"]
+		var tlsContext *apiTlsContext = nil
+		if protocol == "https" {
+			if ok && len(tlsCfg.([]interface{})) > 0 {
+				tlsContext, err = readTlsContextConfig(tlsCfg)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, fmt.Errorf("tls_context is required when protocol is https")
+			}
+		}
+
+		endpointConfig := apiEndpointConfig{
+			EndpointConfig: muleb2b.EndpointConfig{
+				ConfigName:            muleb2b.String(configName),
+				ServerAddress:         muleb2b.String(address),
+				ServerPort:            muleb2b.Integer(port),
+				Path:                  muleb2b.String(path),
+				Protocol:              muleb2b.String(strings.ToUpper(protocol)),
+				ResponseTimeout:       muleb2b.Integer(responseTimeout),
+				ConnectionIdleTimeout: muleb2b.Integer(idleTimeout),
+			},
+			AuthMode:      authMode,
+			TlsContext:    tlsContext,
+			SecretVersion: readSecretVersion(amCfg),
+			SecretSources: readSecretSources(amCfg),
+		}
+		if err := readEndpointUrl(cfg, &endpointConfig, "http", "https"); err != nil {
+			return nil, err
+		}
+		readHttpRequestConfig(cfg, &endpointConfig)
+		endpointConfig.Proxy = expandProxy(cfg["proxy"])
+
+		return &endpointConfig, nil
+
+	}
+	return nil, fmt.Errorf("http_config is required when type is http")
+}
+
+func readAuthModeConfig(data interface{}) (*apiAuthMode, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		if err := validateAuthModeAttributes(cfg, "auth_mode"); err != nil {
+			return nil, err
+		}
+
+		authType := cfg["type"].(string)
+
+		switch authType {
+		case "none":
+			authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType: muleb2b.String("NONE"),
+			}}
+			return &authMode, nil
+
+		case "basic":
+			authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType: muleb2b.String(strings.ToUpper(authType)),
+				Username: muleb2b.String(cfg["username"].(string)),
+				Password: muleb2b.String(cfg["password"].(string)),
+			}}
+			return &authMode, nil
+
+		case "api_key":
+			authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType:       muleb2b.String(strings.ToUpper(authType)),
+				ApiKey:         muleb2b.String(cfg["api_key"].(string)),
+				HttpHeaderName: muleb2b.String(cfg["http_header_name"].(string)),
+			}}
+			return &authMode, nil
+
+		case "client_credentials":
+			authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType:           muleb2b.String(strings.ToUpper(authType)),
+				ClientId:           muleb2b.String(cfg["client_id"].(string)),
+				ClientSecret:       muleb2b.String(cfg["client_secret"].(string)),
+				ClientIdHeader:     muleb2b.String(cfg["client_id_header"].(string)),
+				ClientSecretHeader: muleb2b.String(cfg["client_secret_header"].(string)),
+			}}
+			return &authMode, nil
+
+		case "oauth_token":
+			authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType:     muleb2b.String(strings.ToUpper(authType)),
+				TokenUrl:     muleb2b.String(cfg["token_url"].(string)),
+				ClientId:     muleb2b.String(cfg["client_id"].(string)),
+				ClientSecret: muleb2b.String(cfg["client_secret"].(string)),
+			}}
+			readOAuthTokenConfig(cfg, &authMode)
+			return &authMode, nil
+
+		default:
+			return nil, fmt.Errorf("invalid auth_mode.type specified")
+		}
+	}
+	return nil, nil
+}
+
+// readOAuthTokenConfig reads the token request settings of an oauth_token auth mode
+func readOAuthTokenConfig(cfg map[string]interface{}, authMode *apiAuthMode) {
+	if v, ok := cfg["scope"].(string); ok && v != "" {
+		authMode.Scope = muleb2b.String(v)
+	}
+	if v, ok := cfg["audience"].(string); ok && v != "" {
+		authMode.Audience = muleb2b.String(v)
+	}
+	if v, ok := cfg["resource"].(string); ok && v != "" {
+		authMode.Resource = muleb2b.String(v)
+	}
+	if v, ok := cfg["client_auth_style"].(string); ok && v != "" {
+		authMode.ClientAuthStyle = muleb2b.String(strings.ToUpper(v))
+	}
+	if v := expandStringMap(cfg["token_params"]); len(v) > 0 {
+		authMode.TokenParams = v
+	}
+}
+
+func readTlsContextConfig(data interface{}) (*apiTlsContext, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		_, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		tlsContext := expandTlsContext(data)
+		if err := validateTlsContext(tlsContext); err != nil {
+			return nil, err
+		}
+
+		return tlsContext, nil
+	}
+	return nil, nil
+}
+
+func flattenHttpConfig(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	m := make(map[string]interface{})
+
+	if endpointConfig != nil {
+		if endpointConfig.ConfigName != nil {
+			m["config_name"] = *endpointConfig.ConfigName
+		}
+		if endpointConfig.Url != nil {
+			m["url"] = *endpointConfig.Url
+		} else {
+			if endpointConfig.ServerAddress != nil {
+				m["server_address"] = *endpointConfig.ServerAddress
+			}
+			if endpointConfig.ServerPort != nil {
+				m["server_port"] = *endpointConfig.ServerPort
+			}
+			if endpointConfig.Path != nil {
+				m["path"] = *endpointConfig.Path
+			}
+			if endpointConfig.Protocol != nil {
+				m["protocol"] = strings.ToLower(*endpointConfig.Protocol)
+			}
+		}
+		if endpointConfig.ResponseTimeout != nil {
+			m["response_timeout"] = *endpointConfig.ResponseTimeout
+		}
+		if endpointConfig.ConnectionIdleTimeout != nil {
+			m["connection_idle_timeout"] = *endpointConfig.ConnectionIdleTimeout
+		}
+		if endpointConfig.Method != nil {
+			m["method"] = *endpointConfig.Method
+		}
+		if endpointConfig.ContentType != nil {
+			m["content_type"] = *endpointConfig.ContentType
+		}
+		m["headers"] = endpointConfig.Headers
+		m["query_params"] = endpointConfig.QueryParams
+		m["auth_mode"] = flattenEndpointAuthMode(endpointConfig, sensitive)
+		m["proxy"] = flattenProxy(endpointConfig.Proxy)
+		if endpointConfig.Protocol != nil && strings.ToLower(*endpointConfig.Protocol) == "https" {
+			m["tls_context"] = flattenTlsContext(endpointConfig.TlsContext)
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func flattenSftpConfig(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	m := make(map[string]interface{})
+
+	if endpointConfig != nil {
+		if endpointConfig.ConfigName != nil {
+			m["config_name"] = *endpointConfig.ConfigName
+		}
+		if endpointConfig.Url != nil {
+			m["url"] = *endpointConfig.Url
+		} else {
+			if endpointConfig.ServerAddress != nil {
+				m["server_address"] = *endpointConfig.ServerAddress
+			}
+			if endpointConfig.ServerPort != nil {
+				m["server_port"] = *endpointConfig.ServerPort
+			}
+			if endpointConfig.Path != nil {
+				m["path"] = *endpointConfig.Path
+			}
+		}
+		if endpointConfig.MovedPath != nil {
+			m["archive_path"] = *endpointConfig.MovedPath
+		}
+		if endpointConfig.SizeCheckWaitTime != nil {
+			m["size_check_wait_time"] = *endpointConfig.SizeCheckWaitTime
+		}
+		if endpointConfig.PollingFrequency != nil {
+			m["polling_frequency"] = *endpointConfig.PollingFrequency
+		}
+		if endpointConfig.HostKeyFingerprint != nil {
+			m["host_key_fingerprint"] = *endpointConfig.HostKeyFingerprint
+		}
+		if endpointConfig.KnownHosts != nil {
+			m["known_hosts"] = *endpointConfig.KnownHosts
+		}
+		flattenSftpFileOptions(endpointConfig, m)
+		m["proxy"] = flattenProxy(endpointConfig.Proxy)
+
+		authMode := flattenEndpointAuthMode(endpointConfig, sensitive)
+		if endpointConfig.AuthMode != nil && endpointConfig.AuthMode.AuthType != nil && *endpointConfig.AuthMode.AuthType == "PUBLIC_KEY" && sensitive != nil {
+			am := authMode[0].(map[string]interface{})
+			if sensitive.privateKey != nil {
+				am["private_key"] = *sensitive.privateKey
+			}
+			if sensitive.passphrase != nil {
+				am["passphrase"] = *sensitive.passphrase
+			}
+		}
+		m["auth_mode"] = authMode
+	}
+
+	return []interface{}{m}
+}
+
+func flattenAuthMode(authMode *apiAuthMode, sensitive *sensitiveData) []interface{} {
+	m := make(map[string]interface{})
+
+	if authMode != nil && authMode.AuthType != nil {
+		m["type"] = strings.ToLower(*authMode.AuthType)
+		switch *authMode.AuthType {
+		case "BASIC":
+			if authMode.Username != nil {
+				m["username"] = *authMode.Username
+			}
+			if sensitive != nil && sensitive.password != nil {
+				m["password"] = *sensitive.password
+			}
+		case "API_KEY":
+			if sensitive != nil && sensitive.apiKey != nil {
+				m["api_key"] = *sensitive.apiKey
+			}
+			if authMode.HttpHeaderName != nil {
+				m["http_header_name"] = *authMode.HttpHeaderName
+			}
+		case "CLIENT_CREDENTIALS":
+			if authMode.ClientId != nil {
+				m["client_id"] = *authMode.ClientId
+			}
+			if sensitive != nil && sensitive.clientSecret != nil {
+				m["client_secret"] = *sensitive.clientSecret
+			}
+			if authMode.ClientIdHeader != nil {
+				m["client_id_header"] = *authMode.ClientIdHeader
+			}
+			if authMode.ClientSecretHeader != nil {
+				m["client_secret_header"] = *authMode.ClientSecretHeader
+			}
+		case "OAUTH_TOKEN":
+			if authMode.TokenUrl != nil {
+				m["token_url"] = *authMode.TokenUrl
+			}
+			if authMode.ClientId != nil {
+				m["client_id"] = *authMode.ClientId
+			}
+			if sensitive != nil && sensitive.clientSecret != nil {
+				m["client_secret"] = *sensitive.clientSecret
+			}
+			if authMode.Scope != nil {
+				m["scope"] = *authMode.Scope
+			}
+			if authMode.Audience != nil {
+				m["audience"] = *authMode.Audience
+			}
+			if authMode.Resource != nil {
+				m["resource"] = *authMode.Resource
+			}
+			if authMode.ClientAuthStyle != nil {
+				m["client_auth_style"] = strings.ToLower(*authMode.ClientAuthStyle)
+			}
+			m["token_params"] = authMode.TokenParams
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func flattenTlsContext(context *apiTlsContext) []interface{} {
+	m := make(map[string]interface{})
+	if context != nil {
+		if context.Insecure != nil {
+			m["insecure"] = *context.Insecure
+		}
+		if context.NeedCertificate != nil {
+			m["need_certificate"] = *context.NeedCertificate
+		}
+		if context.TrustStore != nil {
+			if context.TrustStore.Pem != nil {
+				m["trust_store_pem"] = *context.TrustStore.Pem
+			}
+			m["trust_store_certificate_ids"] = context.TrustStore.CertificateIds
+		}
+		if context.KeyStore != nil {
+			if context.KeyStore.CertificatePem != nil {
+				m["key_store_certificate_pem"] = *context.KeyStore.CertificatePem
+			}
+			if context.KeyStore.PrivateKeyPem != nil {
+				m["key_store_private_key_pem"] = *context.KeyStore.PrivateKeyPem
+			}
+			if context.KeyStore.CertificateId != nil {
+				m["key_store_certificate_id"] = *context.KeyStore.CertificateId
+			}
+		}
+		m["tls_versions"] = context.TlsVersions
+		m["cipher_suites"] = context.CipherSuites
+	}
+	return []interface{}{m}
+}
+
+func expandHttpConfig(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					ConfigName:            muleb2b.String(configData["config_name"].(string)),
+					ServerAddress:         muleb2b.String(configData["server_address"].(string)),
+					ServerPort:            muleb2b.Integer(configData["server_port"].(int)),
+					Path:                  muleb2b.String(configData["path"].(string)),
+					Protocol:              muleb2b.String(strings.ToUpper(configData["protocol"].(string))),
+					ResponseTimeout:       muleb2b.Integer(configData["response_timeout"].(int)),
+					ConnectionIdleTimeout: muleb2b.Integer(configData["connection_idle_timeout"].(int)),
+				},
+			}
+			readEndpointUrl(configData, &endpointConfig, "http", "https")
+			readHttpRequestConfig(configData, &endpointConfig)
+			endpointConfig.Proxy = expandProxy(configData["proxy"])
+
+			endpointConfig.AuthMode = expandAuthMode(configData["auth_mode"])
+			endpointConfig.SecretVersion = readSecretVersion(configData["auth_mode"])
+			endpointConfig.SecretSources = readSecretSources(configData["auth_mode"])
+
+			if strings.ToLower(*endpointConfig.Protocol) == "https" {
+				endpointConfig.TlsContext = expandTlsContext(configData["tls_context"])
+			}
+
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func expandSftpConfig(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					ConfigName:        muleb2b.String(configData["config_name"].(string)),
+					ServerAddress:     muleb2b.String(configData["server_address"].(string)),
+					ServerPort:        muleb2b.Integer(configData["server_port"].(int)),
+					Path:              muleb2b.String(configData["path"].(string)),
+					SizeCheckWaitTime: muleb2b.Integer(configData["size_check_wait_time"].(int)),
+					PollingFrequency:  muleb2b.Integer(configData["polling_frequency"].(int)),
+				},
+			}
+
+			if v, ok := configData["archive_path"]; ok {
+				endpointConfig.MovedPath = muleb2b.String(v.(string))
+			}
+			readSftpHostKeyConfig(configData, &endpointConfig)
+			readSftpFileOptions(configData, &endpointConfig)
+			endpointConfig.Proxy = expandProxy(configData["proxy"])
+			readEndpointUrl(configData, &endpointConfig, "sftp")
+
+			if isSftpPublicKeyAuth(configData["auth_mode"]) {
+				authData := configData["auth_mode"].([]interface{})[0].(map[string]interface{})
+				endpointConfig.AuthMode = &apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType: muleb2b.String("PUBLIC_KEY"),
+				}}
+				if v := authData["private_key"].(string); v != "" {
+					endpointConfig.PrivateKey = muleb2b.String(v)
+				}
+				if v := authData["passphrase"].(string); v != "" {
+					endpointConfig.Passphrase = muleb2b.String(v)
+				}
+			} else {
+				endpointConfig.AuthMode = expandAuthMode(configData["auth_mode"])
+			}
+			endpointConfig.SecretVersion = readSecretVersion(configData["auth_mode"])
+			endpointConfig.SecretSources = readSecretSources(configData["auth_mode"])
+
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func expandAuthMode(d interface{}) *apiAuthMode {
+	if d != nil {
+		authList := d.([]interface{})
+		if len(authList) > 0 {
+			authData := authList[0].(map[string]interface{})
+			authType := strings.ToUpper(authData["type"].(string))
+			switch authType {
+			case "NONE":
+				authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType: muleb2b.String(authType),
+				}}
+				return &authMode
+
+			case "BASIC":
+				authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType: muleb2b.String(authType),
+					Username: muleb2b.String(authData["username"].(string)),
+					Password: muleb2b.String(authData["password"].(string)),
+				}}
+				return &authMode
+
+			case "API_KEY":
+				authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType:       muleb2b.String(authType),
+					ApiKey:         muleb2b.String(authData["api_key"].(string)),
+					HttpHeaderName: muleb2b.String(authData["http_header_name"].(string)),
+				}}
+				return &authMode
+
+			case "CLIENT_CREDENTIALS":
+				authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType:           muleb2b.String(authType),
+					ClientId:           muleb2b.String(authData["client_id"].(string)),
+					ClientSecret:       muleb2b.String(authData["client_secret"].(string)),
+					ClientIdHeader:     muleb2b.String(authData["client_id_header"].(string)),
+					ClientSecretHeader: muleb2b.String(authData["client_secret_header"].(string)),
+				}}
+				return &authMode
+
+			case "OAUTH_TOKEN":
+				authMode := apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType:     muleb2b.String(authType),
+					ClientId:     muleb2b.String(authData["client_id"].(string)),
+					ClientSecret: muleb2b.String(authData["client_secret"].(string)),
+					TokenUrl:     muleb2b.String(authData["token_url"].(string)),
+				}}
+				readOAuthTokenConfig(authData, &authMode)
+				return &authMode
+			}
+		}
+	}
+	return nil
+}
+
+func expandTlsContext(d interface{}) *apiTlsContext {
+	if d != nil {
+		tlsList := d.([]interface{})
+		if len(tlsList) > 0 {
+			tlsData := tlsList[0].(map[string]interface{})
+			tlsContext := apiTlsContext{
+				TlsContext: muleb2b.TlsContext{
+					Insecure:        muleb2b.Boolean(tlsData["insecure"].(bool)),
+					NeedCertificate: muleb2b.Boolean(tlsData["need_certificate"].(bool)),
+				},
+				TlsVersions:  expandStringSet(tlsData["tls_versions"]),
+				CipherSuites: expandStringList(tlsData["cipher_suites"]),
+			}
+
+			trustStorePem := tlsData["trust_store_pem"].(string)
+			trustStoreIds := expandStringSet(tlsData["trust_store_certificate_ids"])
+			if trustStorePem != "" || len(trustStoreIds) > 0 {
+				tlsContext.TrustStore = &apiTrustStore{
+					CertificateIds: trustStoreIds,
+				}
+				if trustStorePem != "" {
+					tlsContext.TrustStore.Pem = muleb2b.String(trustStorePem)
+				}
+			}
+
+			keyStoreCertPem := tlsData["key_store_certificate_pem"].(string)
+			keyStoreKeyPem := tlsData["key_store_private_key_pem"].(string)
+			keyStoreCertId := tlsData["key_store_certificate_id"].(string)
+			if keyStoreCertPem != "" || keyStoreKeyPem != "" || keyStoreCertId != "" {
+				tlsContext.KeyStore = &apiKeyStore{}
+				if keyStoreCertPem != "" {
+					tlsContext.KeyStore.CertificatePem = muleb2b.String(keyStoreCertPem)
+				}
+				if keyStoreKeyPem != "" {
+					tlsContext.KeyStore.PrivateKeyPem = muleb2b.String(keyStoreKeyPem)
+				}
+				if keyStoreCertId != "" {
+					tlsContext.KeyStore.CertificateId = muleb2b.String(keyStoreCertId)
+				}
+			}
+
+			return &tlsContext
+		}
+	}
+	return nil
+}
+
+func expandStringSet(d interface{}) []string {
+	var out []string
+	if d != nil {
+		for _, v := range d.(*schema.Set).List() {
+			out = append(out, v.(string))
+		}
+	}
+	return out
+}
+
+func expandStringMap(d interface{}) map[string]string {
+	out := make(map[string]string)
+	if m, ok := d.(map[string]interface{}); ok {
+		for k, v := range m {
+			out[k] = v.(string)
+		}
+	}
+	return out
+}
+
+func expandStringList(d interface{}) []string {
+	var out []string
+	if d != nil {
+		for _, v := range d.([]interface{}) {
+			out = append(out, v.(string))
+		}
+	}
+	return out
+}
+
+// listPartnerEndpoints returns the endpoints owned by the partner in the client's current environment
+func listPartnerEndpoints(client *muleb2b.Client, partnerId string) ([]muleb2b.Endpoint, error) {
+	endpoints, err := client.ListEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []muleb2b.Endpoint
+	if endpoints != nil {
+		for _, endpoint := range *endpoints {
+			if endpoint.PartnerID != nil && *endpoint.PartnerID == partnerId {
+				out = append(out, endpoint)
+			}
+		}
+	}
+	return out, nil
+}
+
+func readAs2Config(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		protocol := cfg["protocol"].(string)
+		mdnMode := cfg["mdn_mode"].(string)
+		asyncMdnUrl := cfg["async_mdn_url"].(string)
+
+		if mdnMode == "async" && asyncMdnUrl == "" {
+			return nil, fmt.Errorf("async_mdn_url is required when mdn_mode is async")
+		}
+
+		configName, ok := cfg["config_name"].(string)
+		if !ok || configName == "" {
+			configName = "as2"
+		}
+
+		tlsCfg, ok := cfg["tls_context"]
+		var tlsContext *apiTlsContext = nil
+		if protocol == "https" {
+			if ok && len(tlsCfg.([]interface{})) > 0 {
+				var err error
+				tlsContext, err = readTlsContextConfig(tlsCfg)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, fmt.Errorf("tls_context is required when protocol is https")
+			}
+		}
+
+		endpointConfig := apiEndpointConfig{
+			EndpointConfig: muleb2b.EndpointConfig{
+				ConfigName:      muleb2b.String(configName),
+				ServerAddress:   muleb2b.String(cfg["server_address"].(string)),
+				ServerPort:      muleb2b.Integer(cfg["server_port"].(int)),
+				Path:            muleb2b.String(cfg["path"].(string)),
+				Protocol:        muleb2b.String(strings.ToUpper(protocol)),
+				ResponseTimeout: muleb2b.Integer(cfg["response_timeout"].(int)),
+			},
+			TlsContext:          tlsContext,
+			As2From:             muleb2b.String(cfg["as2_from"].(string)),
+			As2To:               muleb2b.String(cfg["as2_to"].(string)),
+			SigningAlgorithm:    muleb2b.String(strings.ToUpper(cfg["signing_algorithm"].(string))),
+			EncryptionAlgorithm: muleb2b.String(strings.ToUpper(cfg["encryption_algorithm"].(string))),
+			CompressionEnabled:  muleb2b.Boolean(cfg["compression"].(bool)),
+			MdnMode:             muleb2b.String(strings.ToUpper(mdnMode)),
+			MdnSigned:           muleb2b.Boolean(cfg["mdn_signed"].(bool)),
+			MicAlgorithm:        muleb2b.String(strings.ToUpper(cfg["mic_algorithm"].(string))),
+		}
+
+		if asyncMdnUrl != "" {
+			endpointConfig.AsyncMdnUrl = muleb2b.String(asyncMdnUrl)
+		}
+		if v := cfg["signing_certificate_id"].(string); v != "" {
+			endpointConfig.SigningCertificateId = muleb2b.String(v)
+		}
+		if v := cfg["encryption_certificate_id"].(string); v != "" {
+			endpointConfig.EncryptionCertificateId = muleb2b.String(v)
+		}
+
+		return &endpointConfig, nil
+	}
+	return nil, fmt.Errorf("as2_config is required when type is as2")
+}
+
+func flattenAs2Config(endpointConfig *apiEndpointConfig) []interface{} {
+	m := make(map[string]interface{})
+
+	if endpointConfig != nil {
+		if endpointConfig.ConfigName != nil {
+			m["config_name"] = *endpointConfig.ConfigName
+		}
+		if endpointConfig.ServerAddress != nil {
+			m["server_address"] = *endpointConfig.ServerAddress
+		}
+		if endpointConfig.ServerPort != nil {
+			m["server_port"] = *endpointConfig.ServerPort
+		}
+		if endpointConfig.Path != nil {
+			m["path"] = *endpointConfig.Path
+		}
+		if endpointConfig.Protocol != nil {
+			m["protocol"] = strings.ToLower(*endpointConfig.Protocol)
+		}
+		if endpointConfig.ResponseTimeout != nil {
+			m["response_timeout"] = *endpointConfig.ResponseTimeout
+		}
+		if endpointConfig.Protocol != nil && strings.ToLower(*endpointConfig.Protocol) == "https" {
+			m["tls_context"] = flattenTlsContext(endpointConfig.TlsContext)
+		}
+		if endpointConfig.As2From != nil {
+			m["as2_from"] = *endpointConfig.As2From
+		}
+		if endpointConfig.As2To != nil {
+			m["as2_to"] = *endpointConfig.As2To
+		}
+		if endpointConfig.SigningAlgorithm != nil {
+			m["signing_algorithm"] = strings.ToLower(*endpointConfig.SigningAlgorithm)
+		}
+		if endpointConfig.EncryptionAlgorithm != nil {
+			m["encryption_algorithm"] = strings.ToLower(*endpointConfig.EncryptionAlgorithm)
+		}
+		if endpointConfig.CompressionEnabled != nil {
+			m["compression"] = *endpointConfig.CompressionEnabled
+		}
+		if endpointConfig.MdnMode != nil {
+			m["mdn_mode"] = strings.ToLower(*endpointConfig.MdnMode)
+		}
+		if endpointConfig.MdnSigned != nil {
+			m["mdn_signed"] = *endpointConfig.MdnSigned
+		}
+		if endpointConfig.MicAlgorithm != nil {
+			m["mic_algorithm"] = strings.ToLower(*endpointConfig.MicAlgorithm)
+		}
+		if endpointConfig.AsyncMdnUrl != nil {
+			m["async_mdn_url"] = *endpointConfig.AsyncMdnUrl
+		}
+		if endpointConfig.SigningCertificateId != nil {
+			m["signing_certificate_id"] = *endpointConfig.SigningCertificateId
+		}
+		if endpointConfig.EncryptionCertificateId != nil {
+			m["encryption_certificate_id"] = *endpointConfig.EncryptionCertificateId
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func expandAs2Config(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					ConfigName:      muleb2b.String(configData["config_name"].(string)),
+					ServerAddress:   muleb2b.String(configData["server_address"].(string)),
+					ServerPort:      muleb2b.Integer(configData["server_port"].(int)),
+					Path:            muleb2b.String(configData["path"].(string)),
+					Protocol:        muleb2b.String(strings.ToUpper(configData["protocol"].(string))),
+					ResponseTimeout: muleb2b.Integer(configData["response_timeout"].(int)),
+				},
+				As2From:             muleb2b.String(configData["as2_from"].(string)),
+				As2To:               muleb2b.String(configData["as2_to"].(string)),
+				SigningAlgorithm:    muleb2b.String(strings.ToUpper(configData["signing_algorithm"].(string))),
+				EncryptionAlgorithm: muleb2b.String(strings.ToUpper(configData["encryption_algorithm"].(string))),
+				CompressionEnabled:  muleb2b.Boolean(configData["compression"].(bool)),
+				MdnMode:             muleb2b.String(strings.ToUpper(configData["mdn_mode"].(string))),
+				MdnSigned:           muleb2b.Boolean(configData["mdn_signed"].(bool)),
+				MicAlgorithm:        muleb2b.String(strings.ToUpper(configData["mic_algorithm"].(string))),
+			}
+
+			if v := configData["async_mdn_url"].(string); v != "" {
+				endpointConfig.AsyncMdnUrl = muleb2b.String(v)
+			}
+			if v := configData["signing_certificate_id"].(string); v != "" {
+				endpointConfig.SigningCertificateId = muleb2b.String(v)
+			}
+			if v := configData["encryption_certificate_id"].(string); v != "" {
+				endpointConfig.EncryptionCertificateId = muleb2b.String(v)
+			}
+
+			if strings.ToLower(*endpointConfig.Protocol) == "https" {
+				endpointConfig.TlsContext = expandTlsContext(configData["tls_context"])
+			}
+
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func readFtpConfig(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		ftpsMode := cfg["ftps_mode"].(string)
+
+		var archivePath *string = nil
+		if v, ok := cfg["archive_path"]; ok {
+			archivePath = muleb2b.String(v.(string))
+		}
+
+		configName, ok := cfg["config_name"].(string)
+		if !ok || configName == "" {
+			configName = "ftp"
+		}
+
+		amCfg, ok := cfg["auth_mode"]
+		if !ok {
+			return nil, fmt.Errorf("auth_mode is required in ftp_config")
+		}
+		authMode, err := readAuthModeConfig(amCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsCfg, ok := cfg["tls_context"]
+		var tlsContext *apiTlsContext = nil
+		if ftpsMode != "none" {
+			if ok && len(tlsCfg.([]interface{})) > 0 {
+				tlsContext, err = readTlsContextConfig(tlsCfg)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, fmt.Errorf("tls_context is required when ftps_mode is %s", ftpsMode)
+			}
+		}
+
+		endpointConfig := apiEndpointConfig{
+			EndpointConfig: muleb2b.EndpointConfig{
+				MovedPath:         archivePath,
+				SizeCheckWaitTime: muleb2b.Integer(cfg["size_check_wait_time"].(int)),
+				PollingFrequency:  muleb2b.Integer(cfg["polling_frequency"].(int)),
+				Path:              muleb2b.String(cfg["path"].(string)),
+				ServerAddress:     muleb2b.String(cfg["server_address"].(string)),
+				ServerPort:        muleb2b.Integer(cfg["server_port"].(int)),
+				ConfigName:        muleb2b.String(configName),
+			},
+			AuthMode:      authMode,
+			TlsContext:    tlsContext,
+			PassiveMode:   muleb2b.Boolean(cfg["passive_mode"].(bool)),
+			TransferMode:  muleb2b.String(strings.ToUpper(cfg["transfer_mode"].(string))),
+			FtpsMode:      muleb2b.String(strings.ToUpper(ftpsMode)),
+			Proxy:         expandProxy(cfg["proxy"]),
+			SecretVersion: readSecretVersion(amCfg),
+			SecretSources: readSecretSources(amCfg),
+		}
+		return &endpointConfig, nil
+	}
+	return nil, fmt.Errorf("ftp_config is required when type is ftp")
+}
+
+func flattenFtpConfig(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	m := make(map[string]interface{})
+
+	if endpointConfig != nil {
+		if endpointConfig.ConfigName != nil {
+			m["config_name"] = *endpointConfig.ConfigName
+		}
+		if endpointConfig.ServerAddress != nil {
+			m["server_address"] = *endpointConfig.ServerAddress
+		}
+		if endpointConfig.ServerPort != nil {
+			m["server_port"] = *endpointConfig.ServerPort
+		}
+		if endpointConfig.Path != nil {
+			m["path"] = *endpointConfig.Path
+		}
+		if endpointConfig.MovedPath != nil {
+			m["archive_path"] = *endpointConfig.MovedPath
+		}
+		if endpointConfig.SizeCheckWaitTime != nil {
+			m["size_check_wait_time"] = *endpointConfig.SizeCheckWaitTime
+		}
+		if endpointConfig.PollingFrequency != nil {
+			m["polling_frequency"] = *endpointConfig.PollingFrequency
+		}
+		if endpointConfig.PassiveMode != nil {
+			m["passive_mode"] = *endpointConfig.PassiveMode
+		}
+		if endpointConfig.TransferMode != nil {
+			m["transfer_mode"] = strings.ToLower(*endpointConfig.TransferMode)
+		}
+		if endpointConfig.FtpsMode != nil {
+			m["ftps_mode"] = strings.ToLower(*endpointConfig.FtpsMode)
+			if *endpointConfig.FtpsMode != "NONE" {
+				m["tls_context"] = flattenTlsContext(endpointConfig.TlsContext)
+			}
+		}
+		m["auth_mode"] = flattenEndpointAuthMode(endpointConfig, sensitive)
+		m["proxy"] = flattenProxy(endpointConfig.Proxy)
+	}
+
+	return []interface{}{m}
+}
+
+func expandFtpConfig(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					ConfigName:        muleb2b.String(configData["config_name"].(string)),
+					ServerAddress:     muleb2b.String(configData["server_address"].(string)),
+					ServerPort:        muleb2b.Integer(configData["server_port"].(int)),
+					Path:              muleb2b.String(configData["path"].(string)),
+					SizeCheckWaitTime: muleb2b.Integer(configData["size_check_wait_time"].(int)),
+					PollingFrequency:  muleb2b.Integer(configData["polling_frequency"].(int)),
+				},
+				PassiveMode:  muleb2b.Boolean(configData["passive_mode"].(bool)),
+				TransferMode: muleb2b.String(strings.ToUpper(configData["transfer_mode"].(string))),
+				FtpsMode:     muleb2b.String(strings.ToUpper(configData["ftps_mode"].(string))),
+			}
+
+			if v, ok := configData["archive_path"]; ok {
+				endpointConfig.MovedPath = muleb2b.String(v.(string))
+			}
+
+			endpointConfig.AuthMode = expandAuthMode(configData["auth_mode"])
+			endpointConfig.SecretVersion = readSecretVersion(configData["auth_mode"])
+			endpointConfig.SecretSources = readSecretSources(configData["auth_mode"])
+			endpointConfig.Proxy = expandProxy(configData["proxy"])
+
+			if *endpointConfig.FtpsMode != "NONE" {
+				endpointConfig.TlsContext = expandTlsContext(configData["tls_context"])
+			}
+
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func readAnypointMqConfig(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		if err := validateSecretAttribute(cfg, "anypoint_mq_config", "client_secret", true); err != nil {
+			return nil, err
+		}
+
+		configName, ok := cfg["config_name"].(string)
+		if !ok || configName == "" {
+			configName = "anypoint_mq"
+		}
+
+		endpointConfig := apiEndpointConfig{
+			EndpointConfig: muleb2b.EndpointConfig{
+				ConfigName: muleb2b.String(configName),
+			},
+			AuthMode: &apiAuthMode{AuthMode: muleb2b.AuthMode{
+				AuthType:     muleb2b.String("CLIENT_CREDENTIALS"),
+				ClientId:     muleb2b.String(cfg["client_id"].(string)),
+				ClientSecret: muleb2b.String(cfg["client_secret"].(string)),
+			}},
+			Destination:       muleb2b.String(cfg["destination"].(string)),
+			DestinationType:   muleb2b.String(strings.ToUpper(cfg["destination_type"].(string))),
+			Region:            muleb2b.String(cfg["region"].(string)),
+			MessageProperties: expandStringMap(cfg["message_properties"]),
+			SecretVersion:     cfg["secret_version"].(int),
+			SecretSources:     readSecretSources(data),
+		}
+		return &endpointConfig, nil
+	}
+	return nil, fmt.Errorf("anypoint_mq_config is required when type is anypoint_mq")
+}
+
+func flattenAnypointMqConfig(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	m := make(map[string]interface{})
+
+	if endpointConfig != nil {
+		if endpointConfig.ConfigName != nil {
+			m["config_name"] = *endpointConfig.ConfigName
+		}
+		if endpointConfig.Destination != nil {
+			m["destination"] = *endpointConfig.Destination
+		}
+		if endpointConfig.DestinationType != nil {
+			m["destination_type"] = strings.ToLower(*endpointConfig.DestinationType)
+		}
+		if endpointConfig.Region != nil {
+			m["region"] = *endpointConfig.Region
+		}
+		if endpointConfig.AuthMode != nil && endpointConfig.AuthMode.ClientId != nil {
+			m["client_id"] = *endpointConfig.AuthMode.ClientId
+		}
+		if sensitive != nil && sensitive.clientSecret != nil {
+			m["client_secret"] = *sensitive.clientSecret
+		}
+		m["message_properties"] = endpointConfig.MessageProperties
+		m["secret_version"] = endpointConfig.SecretVersion
+		for k, v := range endpointConfig.SecretSources {
+			m[k] = v
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func expandAnypointMqConfig(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := apiEndpointConfig{
+				EndpointConfig: muleb2b.EndpointConfig{
+					ConfigName: muleb2b.String(configData["config_name"].(string)),
+				},
+				AuthMode: &apiAuthMode{AuthMode: muleb2b.AuthMode{
+					AuthType: muleb2b.String("CLIENT_CREDENTIALS"),
+					ClientId: muleb2b.String(configData["client_id"].(string)),
+				}},
+				Destination:       muleb2b.String(configData["destination"].(string)),
+				DestinationType:   muleb2b.String(strings.ToUpper(configData["destination_type"].(string))),
+				Region:            muleb2b.String(configData["region"].(string)),
+				MessageProperties: expandStringMap(configData["message_properties"]),
+				SecretVersion:     configData["secret_version"].(int),
+				SecretSources:     readSecretSources(d),
+			}
+
+			if v, ok := configData["client_secret"].(string); ok && v != "" {
+				endpointConfig.AuthMode.ClientSecret = muleb2b.String(v)
+			}
+
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+// readObjectStorageConfig reads the attributes shared by the object storage configurations
+func readObjectStorageConfig(cfg map[string]interface{}, configName string) apiEndpointConfig {
+	if v, ok := cfg["config_name"].(string); ok && v != "" {
+		configName = v
+	}
+
+	endpointConfig := apiEndpointConfig{
+		EndpointConfig: muleb2b.EndpointConfig{
+			ConfigName:       muleb2b.String(configName),
+			PollingFrequency: muleb2b.Integer(cfg["polling_frequency"].(int)),
+		},
+		SecretVersion: cfg["secret_version"].(int),
+	}
+	if v, ok := cfg["prefix"].(string); ok && v != "" {
+		endpointConfig.Prefix = muleb2b.String(v)
+	}
+	if v, ok := cfg["archive_prefix"].(string); ok && v != "" {
+		endpointConfig.ArchivePrefix = muleb2b.String(v)
+	}
+	if v, ok := cfg["endpoint_url"].(string); ok && v != "" {
+		endpointConfig.ServiceEndpoint = muleb2b.String(v)
+	}
+	return endpointConfig
+}
+
+// flattenObjectStorageConfig flattens the attributes shared by the object storage configurations
+func flattenObjectStorageConfig(endpointConfig *apiEndpointConfig) map[string]interface{} {
+	m := make(map[string]interface{})
+	if endpointConfig.ConfigName != nil {
+		m["config_name"] = *endpointConfig.ConfigName
+	}
+	if endpointConfig.Prefix != nil {
+		m["prefix"] = *endpointConfig.Prefix
+	}
+	if endpointConfig.ArchivePrefix != nil {
+		m["archive_prefix"] = *endpointConfig.ArchivePrefix
+	}
+	if endpointConfig.PollingFrequency != nil {
+		m["polling_frequency"] = *endpointConfig.PollingFrequency
+	}
+	if endpointConfig.ServiceEndpoint != nil {
+		m["endpoint_url"] = *endpointConfig.ServiceEndpoint
+	}
+	m["secret_version"] = endpointConfig.SecretVersion
+	for k, v := range endpointConfig.SecretSources {
+		m[k] = v
+	}
+	return m
+}
+
+func readS3Config(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		if err := validateSecretAttribute(cfg, "s3_config", "secret_access_key", true); err != nil {
+			return nil, err
+		}
+
+		endpointConfig := readObjectStorageConfig(cfg, "s3")
+		endpointConfig.Bucket = muleb2b.String(cfg["bucket"].(string))
+		endpointConfig.Region = muleb2b.String(cfg["region"].(string))
+		endpointConfig.AccessKeyId = muleb2b.String(cfg["access_key_id"].(string))
+		endpointConfig.SecretAccessKey = muleb2b.String(cfg["secret_access_key"].(string))
+		endpointConfig.SecretSources = readSecretSources(data)
+		return &endpointConfig, nil
+	}
+	return nil, fmt.Errorf("s3_config is required when type is s3")
+}
+
+func flattenS3Config(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	if endpointConfig == nil {
+		return []interface{}{map[string]interface{}{}}
+	}
+
+	m := flattenObjectStorageConfig(endpointConfig)
+	if endpointConfig.Bucket != nil {
+		m["bucket"] = *endpointConfig.Bucket
+	}
+	if endpointConfig.Region != nil {
+		m["region"] = *endpointConfig.Region
+	}
+	if endpointConfig.AccessKeyId != nil {
+		m["access_key_id"] = *endpointConfig.AccessKeyId
+	}
+	if sensitive != nil && sensitive.secretAccessKey != nil {
+		m["secret_access_key"] = *sensitive.secretAccessKey
+	}
+	return []interface{}{m}
+}
+
+func expandS3Config(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := readObjectStorageConfig(configData, "s3")
+			endpointConfig.Bucket = muleb2b.String(configData["bucket"].(string))
+			endpointConfig.Region = muleb2b.String(configData["region"].(string))
+			endpointConfig.AccessKeyId = muleb2b.String(configData["access_key_id"].(string))
+			endpointConfig.SecretSources = readSecretSources(d)
+			if v, ok := configData["secret_access_key"].(string); ok && v != "" {
+				endpointConfig.SecretAccessKey = muleb2b.String(v)
+			}
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func readAzureBlobConfig(data interface{}) (*apiEndpointConfig, error) {
+	config := data.([]interface{})
+	for _, raw := range config {
+		cfg, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse: %#v", raw)
+		}
+
+		if err := validateSecretAttribute(cfg, "azure_blob_config", "account_key", true); err != nil {
+			return nil, err
+		}
+
+		endpointConfig := readObjectStorageConfig(cfg, "azure_blob")
+		endpointConfig.Container = muleb2b.String(cfg["container"].(string))
+		endpointConfig.AccountName = muleb2b.String(cfg["account_name"].(string))
+		endpointConfig.AccountKey = muleb2b.String(cfg["account_key"].(string))
+		endpointConfig.SecretSources = readSecretSources(data)
+		return &endpointConfig, nil
+	}
+	return nil, fmt.Errorf("azure_blob_config is required when type is azure_blob")
+}
+
+func flattenAzureBlobConfig(endpointConfig *apiEndpointConfig, sensitive *sensitiveData) []interface{} {
+	if endpointConfig == nil {
+		return []interface{}{map[string]interface{}{}}
+	}
+
+	m := flattenObjectStorageConfig(endpointConfig)
+	if endpointConfig.Container != nil {
+		m["container"] = *endpointConfig.Container
+	}
+	if endpointConfig.AccountName != nil {
+		m["account_name"] = *endpointConfig.AccountName
+	}
+	if sensitive != nil && sensitive.accountKey != nil {
+		m["account_key"] = *sensitive.accountKey
+	}
+	return []interface{}{m}
+}
+
+func expandAzureBlobConfig(d interface{}) *apiEndpointConfig {
+	if d != nil {
+		configList := d.([]interface{})
+		if len(configList) > 0 {
+			configData := configList[0].(map[string]interface{})
+			endpointConfig := readObjectStorageConfig(configData, "azure_blob")
+			endpointConfig.Container = muleb2b.String(configData["container"].(string))
+			endpointConfig.AccountName = muleb2b.String(configData["account_name"].(string))
+			endpointConfig.SecretSources = readSecretSources(d)
+			if v, ok := configData["account_key"].(string); ok && v != "" {
+				endpointConfig.AccountKey = muleb2b.String(v)
+			}
+			return &endpointConfig
+		}
+	}
+	return nil
+}
+
+func expandRetryPolicy(d interface{}) *apiRetryPolicy {
+	if d != nil {
+		policyList := d.(*schema.Set).List()
+		if len(policyList) > 0 {
+			policyData := policyList[0].(map[string]interface{})
+			policy := apiRetryPolicy{
+				MaxAttempts:     muleb2b.Integer(policyData["max_attempts"].(int)),
+				BackoffInterval: muleb2b.Integer(policyData["backoff_interval"].(int)),
+			}
+			if codes, ok := policyData["retryable_status_codes"].(*schema.Set); ok {
+				for _, code := range codes.List() {
+					policy.RetryableStatusCodes = append(policy.RetryableStatusCodes, code.(int))
+				}
+				sort.Ints(policy.RetryableStatusCodes)
+			}
+			if v, ok := policyData["dead_letter_endpoint_id"].(string); ok && v != "" {
+				policy.DeadLetterEndpointId = muleb2b.String(v)
+			}
+			return &policy
+		}
+	}
+	return nil
+}
+
+func flattenRetryPolicy(policy *apiRetryPolicy) []interface{} {
+	if policy == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	if policy.MaxAttempts != nil {
+		m["max_attempts"] = *policy.MaxAttempts
+	}
+	if policy.BackoffInterval != nil {
+		m["backoff_interval"] = *policy.BackoffInterval
+	}
+	codes := make([]interface{}, 0, len(policy.RetryableStatusCodes))
+	for _, code := range policy.RetryableStatusCodes {
+		codes = append(codes, code)
+	}
+	m["retryable_status_codes"] = codes
+	if policy.DeadLetterEndpointId != nil {
+		m["dead_letter_endpoint_id"] = *policy.DeadLetterEndpointId
+	}
+	return []interface{}{m}
+}
+
+// validateRetryPolicy ensures a retry_policy block is only set on send endpoints, and only retries HTTP status codes
+// on endpoints that receive HTTP responses
+func validateRetryPolicy(policies *schema.Set, role, endType string) error {
+	if policies.Len() == 0 {
+		return nil
+	}
+	if role != "send" {
+		return fmt.Errorf("retry_policy can only be set when role is send")
+	}
+
+	policy := policies.List()[0].(map[string]interface{})
+	if codes, ok := policy["retryable_status_codes"].(*schema.Set); ok && codes.Len() > 0 && endType != "http" && endType != "as2" {
+		return fmt.Errorf("retry_policy.retryable_status_codes can only be set when type is http or as2")
+	}
+	return nil
+}
+
+// validateDeadLetterEndpoint ensures the dead letter endpoint of a retry policy is another send endpoint
+func validateDeadLetterEndpoint(client *muleb2b.Client, envId, id string, policy *apiRetryPolicy) error {
+	if policy == nil || policy.DeadLetterEndpointId == nil {
+		return nil
+	}
+
+	deadLetterId := *policy.DeadLetterEndpointId
+	if deadLetterId == id {
+		return fmt.Errorf("retry_policy.dead_letter_endpoint_id can't reference the endpoint itself")
+	}
+
+	deadLetter, err := getEndpoint(client, envId, deadLetterId)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dead letter endpoint (%s): %s", deadLetterId, err)
+	}
+	if deadLetter.EndpointRole == nil || !strings.EqualFold(*deadLetter.EndpointRole, "send") {
+		return fmt.Errorf("dead letter endpoint (%s) must have role send", deadLetterId)
+	}
+	return nil
+}
+
+// sensitiveDataFromConfig keeps the secrets of an expanded endpoint configuration so they can be written back to the state
+func sensitiveDataFromConfig(endpointConfig *apiEndpointConfig) *sensitiveData {
+	if endpointConfig == nil {
+		return nil
+	}
+	if endpointConfig.PrivateKey != nil {
+		return &sensitiveData{
+			privateKey: endpointConfig.PrivateKey,
+			passphrase: endpointConfig.Passphrase,
+		}
+	}
+	if endpointConfig.SecretAccessKey != nil {
+		return &sensitiveData{
+			secretAccessKey: endpointConfig.SecretAccessKey,
+		}
+	}
+	if endpointConfig.AccountKey != nil {
+		return &sensitiveData{
+			accountKey: endpointConfig.AccountKey,
+		}
+	}
+	return sensitiveDataFromAuthMode(endpointConfig.AuthMode)
+}
+
+// sensitiveDataFromAuthMode keeps the secret of the auth mode so it can be written back to the state
+func sensitiveDataFromAuthMode(authMode *apiAuthMode) *sensitiveData {
+	if authMode == nil {
+		return nil
+	}
+	if authMode.Password != nil {
+		return &sensitiveData{
+			password: authMode.Password,
+		}
+	} else if authMode.ClientSecret != nil {
+		return &sensitiveData{
+			clientSecret: authMode.ClientSecret,
+		}
+	} else if authMode.ApiKey != nil {
+		return &sensitiveData{
+			apiKey: authMode.ApiKey,
+		}
+	}
+	return nil
+}
+
+// validateSftpKeys checks the private key and host key settings of an expanded sftp_config
+func validateSftpKeys(endpointConfig *apiEndpointConfig) error {
+	if endpointConfig == nil {
+		return nil
+	}
+
+	if endpointConfig.PrivateKey != nil {
+		passphrase := ""
+		if endpointConfig.Passphrase != nil {
+			passphrase = *endpointConfig.Passphrase
+		}
+		if err := validateSftpPrivateKey(*endpointConfig.PrivateKey, passphrase); err != nil {
+			return err
+		}
+	}
+
+	if endpointConfig.HostKeyFingerprint != nil && endpointConfig.KnownHosts != nil {
+		return fmt.Errorf("only one of host_key_fingerprint or known_hosts may be set in sftp_config")
+	}
+	if endpointConfig.HostKeyFingerprint != nil {
+		if err := validateSftpHostKeyFingerprint(*endpointConfig.HostKeyFingerprint); err != nil {
+			return err
+		}
+	}
+	if endpointConfig.KnownHosts != nil {
+		if err := validateSftpKnownHosts(*endpointConfig.KnownHosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var sftpSha256FingerprintRegexp = regexp.MustCompile(`^SHA256:[A-Za-z0-9+/]{43}=?$`)
+var sftpMd5FingerprintRegexp = regexp.MustCompile(`^(MD5:)?([0-9a-fA-F]{2}:){15}[0-9a-fA-F]{2}$`)
+
+// validateSftpHostKeyFingerprint accepts fingerprints in the formats printed by ssh-keygen -l,
+// i.e. SHA256:<base64> or MD5:<hex pairs>
+func validateSftpHostKeyFingerprint(fingerprint string) error {
+	if !sftpSha256FingerprintRegexp.MatchString(fingerprint) && !sftpMd5FingerprintRegexp.MatchString(fingerprint) {
+		return fmt.Errorf("host_key_fingerprint (%s) must be in the SHA256:<base64> or MD5:<hex pairs> format", fingerprint)
+	}
+	return nil
+}
+
+func validateSftpKnownHosts(knownHosts string) error {
+	rest := []byte(knownHosts)
+	for {
+		_, _, _, _, r, err := ssh.ParseKnownHosts(rest)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("known_hosts is not valid: %s", err)
+		}
+		rest = r
+	}
+}
+
+func validateSftpPrivateKey(privateKey, passphrase string) error {
+	var err error
+	if passphrase != "" {
+		_, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	} else {
+		_, err = ssh.ParsePrivateKey([]byte(privateKey))
+	}
+
+	if err != nil {
+		return fmt.Errorf("private_key could not be parsed: %s", err)
+	}
+	return nil
+}
+
+var tlsVersions = []string{"TLSv1.1", "TLSv1.2", "TLSv1.3"}
+var cipherSuiteRegexp = regexp.MustCompile(`^TLS_[A-Z0-9_]+$`)
+
+// validateTlsContext parses the PEM contents of the trust and key stores so that invalid certificates
+// and keys are reported before the endpoint is created or updated
+func validateTlsContext(tlsContext *apiTlsContext) error {
+	if tlsContext == nil {
+		return nil
+	}
+
+	if tlsContext.TrustStore != nil && tlsContext.TrustStore.Pem != nil {
+		if err := validateCertificatesPem(*tlsContext.TrustStore.Pem); err != nil {
+			return fmt.Errorf("trust_store_pem is not valid: %s", err)
+		}
+	}
+
+	if ks := tlsContext.KeyStore; ks != nil {
+		if ks.CertificatePem != nil && ks.CertificateId != nil {
+			return fmt.Errorf("only one of key_store_certificate_pem or key_store_certificate_id may be set in tls_context")
+		}
+		if ks.PrivateKeyPem == nil {
+			return fmt.Errorf("key_store_private_key_pem is required when a key store certificate is set in tls_context")
+		}
+		if ks.CertificatePem == nil && ks.CertificateId == nil {
+			return fmt.Errorf("key_store_certificate_pem or key_store_certificate_id is required when key_store_private_key_pem is set in tls_context")
+		}
+		if ks.CertificatePem != nil {
+			if _, err := tls.X509KeyPair([]byte(*ks.CertificatePem), []byte(*ks.PrivateKeyPem)); err != nil {
+				return fmt.Errorf("key_store_certificate_pem and key_store_private_key_pem are not a valid key pair: %s", err)
+			}
+		} else if block, _ := pem.Decode([]byte(*ks.PrivateKeyPem)); block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return fmt.Errorf("key_store_private_key_pem does not contain a PEM encoded private key")
+		}
+	}
+
+	for _, v := range tlsContext.TlsVersions {
+		found := false
+		for _, allowed := range tlsVersions {
+			if v == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("tls_versions value (%s) must be one of: %s", v, strings.Join(tlsVersions, ", "))
+		}
+	}
+
+	for _, v := range tlsContext.CipherSuites {
+		if !cipherSuiteRegexp.MatchString(v) {
+			return fmt.Errorf("cipher_suites value (%s) must be an IANA cipher suite name, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", v)
+		}
+	}
+	return nil
+}
+
+// validateCertificatesPem ensures the content contains at least one PEM encoded certificate and nothing else
+func validateCertificatesPem(content string) error {
+	rest := []byte(content)
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block (%s)", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count == 0 {
+		return fmt.Errorf("no PEM encoded certificates found")
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return fmt.Errorf("content after the last certificate is not PEM encoded")
+	}
+	return nil
+}
+
+// endpointUrlDefaultPorts are the ports used when the url of an endpoint doesn't include one
+var endpointUrlDefaultPorts = map[string]int{"http": 80, "https": 443, "sftp": 22, "ftp": 21, "ftps": 990}
+
+// parseEndpointUrl splits the url of an endpoint into its scheme, server address, server port and path
+func parseEndpointUrl(raw string, schemes ...string) (scheme, address string, port int, path string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", 0, "", err
+	}
+
+	scheme = strings.ToLower(u.Scheme)
+	allowed := false
+	for _, s := range schemes {
+		if scheme == s {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", "", 0, "", fmt.Errorf("scheme must be one of: %s", strings.Join(schemes, ", "))
+	}
+
+	address = u.Hostname()
+	if address == "" {
+		return "", "", 0, "", fmt.Errorf("server address is missing")
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", 0, "", fmt.Errorf("credentials, query, and fragment are not supported")
+	}
+
+	port = endpointUrlDefaultPorts[scheme]
+	if p := u.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return "", "", 0, "", fmt.Errorf("port (%s) is not valid", p)
+		}
+	}
+
+	path = u.Path
+	if path == "" {
+		path = "/"
+	}
+	return scheme, address, port, path, nil
+}
+
+// formatEndpointUrl builds the url of an endpoint, leaving out the port when it is the default of the scheme
+func formatEndpointUrl(scheme, address string, port int, path string) string {
+	host := address
+	if port != 0 && port != endpointUrlDefaultPorts[scheme] {
+		host = fmt.Sprintf("%s:%d", address, port)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := url.URL{Scheme: scheme, Host: host, Path: path}
+	return u.String()
+}
+
+// validateEndpointUrl returns a validation function that ensures an endpoint url can be parsed
+func validateEndpointUrl(schemes ...string) schema.SchemaValidateFunc {
+	return func(value interface{}, key string) (warnings []string, errors []error) {
+		v, ok := value.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %q to be string", key))
+			return warnings, errors
+		}
+
+		if _, _, _, _, err := parseEndpointUrl(v, schemes...); err != nil {
+			errors = append(errors, fmt.Errorf("%q is not a valid url: %s", key, err))
+		}
+		return warnings, errors
+	}
+}
+
+// readEndpointUrl replaces the protocol, server address, server port and path of an http_config or sftp_config block
+// with the ones parsed from its url, when it is set
+func readEndpointUrl(cfg map[string]interface{}, endpointConfig *apiEndpointConfig, schemes ...string) error {
+	raw, ok := cfg["url"].(string)
+	if !ok || raw == "" {
+		return nil
+	}
+
+	scheme, address, port, path, err := parseEndpointUrl(raw, schemes...)
+	if err != nil {
+		return fmt.Errorf("url (%s) is not valid: %s", raw, err)
+	}
+
+	endpointConfig.Url = muleb2b.String(raw)
+	endpointConfig.ServerAddress = muleb2b.String(address)
+	endpointConfig.ServerPort = muleb2b.Integer(port)
+	endpointConfig.Path = muleb2b.String(path)
+	if scheme == "http" || scheme == "https" {
+		endpointConfig.Protocol = muleb2b.String(strings.ToUpper(scheme))
+	}
+	return nil
+}
+
+// validateEndpointUrlConfig ensures an http_config or sftp_config block either sets its url or the fields it replaces
+func validateEndpointUrlConfig(cfg map[string]interface{}, path string, fields ...string) error {
+	isSet := func(k string) bool {
+		switch v := cfg[k].(type) {
+		case string:
+			return v != ""
+		case int:
+			return v != 0
+		}
+		return false
+	}
+
+	for _, field := range fields {
+		if isSet("url") && isSet(field) {
+			return fmt.Errorf("%s.url conflicts with %s.%s, only one of them may be set", path, path, field)
+		}
+		if !isSet("url") && !isSet(field) {
+			return fmt.Errorf("%s.%s is required when %s.url is not set", path, field, path)
+		}
+	}
+	return nil
+}
+
+// endpointUrl returns the url of an endpoint built from its configuration
+func endpointUrl(endpoint *apiEndpoint) string {
+	cfg := endpoint.Config
+	if cfg == nil {
+		return ""
+	}
+
+	// Object storage endpoints are identified by their bucket or container instead of a server
+	switch *endpoint.EndpointType {
+	case "s3":
+		if cfg.Bucket == nil {
+			return ""
+		}
+		return "s3://" + *cfg.Bucket + "/" + objectStoragePrefix(cfg)
+	case "azure_blob":
+		if cfg.AccountName == nil || cfg.Container == nil {
+			return ""
+		}
+		if cfg.ServiceEndpoint != nil {
+			return fmt.Sprintf("%s/%s/%s", strings.TrimRight(*cfg.ServiceEndpoint, "/"), *cfg.Container, objectStoragePrefix(cfg))
+		}
+		return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", *cfg.AccountName, *cfg.Container, objectStoragePrefix(cfg))
+	}
+
+	if cfg.ServerAddress == nil || *cfg.ServerAddress == "" {
+		return ""
+	}
+
+	var scheme string
+	switch *endpoint.EndpointType {
+	case "http", "as2":
+		if cfg.Protocol == nil {
+			return ""
+		}
+		scheme = strings.ToLower(*cfg.Protocol)
+	case "sftp":
+		scheme = "sftp"
+	case "ftp":
+		scheme = "ftp"
+		if cfg.FtpsMode != nil && *cfg.FtpsMode != "NONE" {
+			scheme = "ftps"
+		}
+	default:
+		return ""
+	}
+
+	port := 0
+	if cfg.ServerPort != nil {
+		port = *cfg.ServerPort
+	}
+	path := ""
+	if cfg.Path != nil {
+		path = *cfg.Path
+	}
+	return formatEndpointUrl(scheme, *cfg.ServerAddress, port, path)
+}
+
+func objectStoragePrefix(cfg *apiEndpointConfig) string {
+	if cfg.Prefix == nil {
+		return ""
+	}
+	return strings.TrimPrefix(*cfg.Prefix, "/")
+}
@@ -0,0 +1,186 @@
+package b2b
+
+import (
+	"fmt"
+	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
+	"regexp"
+)
+
+// identifierRule describes the values allowed for an identifier type and qualifier code.
+// An empty qualifierCode applies the rule to every qualifier of the identifier type.
+type identifierRule struct {
+	identifierType string
+	qualifierCode  string
+	description    string
+	pattern        *regexp.Regexp
+	minLength      int
+	maxLength      int
+}
+
+// Format rules for the standard identifier types. Custom rules configured on the provider take precedence.
+var defaultIdentifierRules = []identifierRule{
+	{
+		identifierType: "X12-ISA",
+		qualifierCode:  "01",
+		description:    "a 9 digit DUNS number",
+		pattern:        regexp.MustCompile(`^[0-9]{9}$`),
+	},
+	{
+		identifierType: "X12-ISA",
+		qualifierCode:  "12",
+		description:    "a 10 digit telephone number without punctuation",
+		pattern:        regexp.MustCompile(`^[0-9]{10}$`),
+	},
+	{
+		identifierType: "X12-ISA",
+		qualifierCode:  "ZZ",
+		description:    "at most 15 characters",
+		minLength:      1,
+		maxLength:      15,
+	},
+	{
+		identifierType: "X12-ISA",
+		description:    "at most 15 characters",
+		minLength:      1,
+		maxLength:      15,
+	},
+	{
+		identifierType: "AS2",
+		description:    "1 to 128 printable ASCII characters without spaces, quotes or backslashes",
+		pattern:        regexp.MustCompile(`^[!#-\[\]-~]{1,128}$`),
+	},
+	{
+		identifierType: "EDIFACT-UNB",
+		qualifierCode:  "1",
+		description:    "a 9 digit DUNS number",
+		pattern:        regexp.MustCompile(`^[0-9]{9}$`),
+	},
+	{
+		identifierType: "EDIFACT-UNB",
+		qualifierCode:  "14",
+		description:    "a 13 digit GLN",
+		pattern:        regexp.MustCompile(`^[0-9]{13}$`),
+	},
+	{
+		identifierType: "EDIFACT-UNB",
+		description:    "at most 35 characters",
+		minLength:      1,
+		maxLength:      35,
+	},
+}
+
+func expandIdentifierRules(v interface{}) ([]identifierRule, error) {
+	var rules []identifierRule
+	for _, r := range v.([]interface{}) {
+		configData := r.(map[string]interface{})
+
+		rule := identifierRule{
+			identifierType: configData["identifier_type"].(string),
+			qualifierCode:  configData["qualifier_code"].(string),
+			description:    configData["description"].(string),
+			minLength:      configData["min_length"].(int),
+			maxLength:      configData["max_length"].(int),
+		}
+
+		if pattern := configData["pattern"].(string); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("identifier_rule for (%s) has an invalid pattern: %s", rule.identifierType, err)
+			}
+			rule.pattern = re
+		}
+
+		if rule.description == "" {
+			rule.description = describeIdentifierRule(rule)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func describeIdentifierRule(rule identifierRule) string {
+	desc := ""
+	if rule.pattern != nil {
+		desc = fmt.Sprintf("matching %s", rule.pattern.String())
+	}
+	if rule.minLength > 0 || rule.maxLength > 0 {
+		if desc != "" {
+			desc += " and "
+		}
+		if rule.maxLength > 0 {
+			desc += fmt.Sprintf("%d to %d characters", rule.minLength, rule.maxLength)
+		} else {
+			desc += fmt.Sprintf("at least %d characters", rule.minLength)
+		}
+	}
+	return desc
+}
+
+func (rule identifierRule) validate(value string) bool {
+	if rule.minLength > 0 && len(value) < rule.minLength {
+		return false
+	}
+	if rule.maxLength > 0 && len(value) > rule.maxLength {
+		return false
+	}
+	if rule.pattern != nil && !rule.pattern.MatchString(value) {
+		return false
+	}
+	return true
+}
+
+// findIdentifierRule returns the rule for the identifier type and qualifier code, preferring custom rules over
+// the defaults and exact qualifier matches over rules for the whole identifier type. It returns nil if no rule applies.
+func findIdentifierRule(custom []identifierRule, identifierType, qualifierCode string) *identifierRule {
+	for _, rules := range [][]identifierRule{custom, defaultIdentifierRules} {
+		for _, code := range []string{qualifierCode, ""} {
+			for i := range rules {
+				if rules[i].identifierType == identifierType && rules[i].qualifierCode == code {
+					return &rules[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateIdentifierValues checks each identifier value against the rule for its identifier type and qualifier, custom
+// rules are the identifier rules configured on the provider
+func validateIdentifierValues(client *muleb2b.Client, custom []identifierRule, envId string, identifiers []*muleb2b.Identifier) error {
+	if len(identifiers) == 0 {
+		return nil
+	}
+
+	client.SetEnvironment(envId)
+
+	identifierTypes, err := client.ListIdentifierTypes()
+	if err != nil {
+		return err
+	}
+
+	for _, identifier := range identifiers {
+		for _, identifierType := range identifierTypes {
+			if identifierType.Name == nil {
+				continue
+			}
+
+			qualifiers, _ := identifierType.GetIdentifierTypeQualifiersById(*identifier.IdentifierTypeQualifierId)
+			if len(qualifiers) == 0 {
+				continue
+			}
+
+			code := ""
+			if qualifiers[0].Code != nil {
+				code = *qualifiers[0].Code
+			}
+
+			rule := findIdentifierRule(custom, *identifierType.Name, code)
+			if rule != nil && !rule.validate(*identifier.Value) {
+				return fmt.Errorf("identifier value (%s) is not valid for %s qualifier (%s), expected %s", *identifier.Value, *identifierType.Name, code, rule.description)
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/avioconsulting/muleb2b-api-go/muleb2b"
 	"github.com/hashicorp/terraform/helper/schema"
+	"sync"
 )
 
 func readIdentifierConfig(data interface{}) ([]*muleb2b.Identifier, error) {
@@ -17,7 +18,7 @@ func readIdentifierConfig(data interface{}) ([]*muleb2b.Identifier, error) {
 
 		identifier := muleb2b.Identifier{
 			IdentifierTypeQualifierId: muleb2b.String(cfg["identifier_type_id"].(string)),
-			Status:                    muleb2b.String("ACTIVE"),
+			Status:                    muleb2b.String(cfg["status"].(string)),
 			Value:                     muleb2b.String(cfg["value"].(string)),
 		}
 
@@ -27,7 +28,7 @@ func readIdentifierConfig(data interface{}) ([]*muleb2b.Identifier, error) {
 	return identifiers, nil
 }
 func flattenIdentifiers(identifiers []*muleb2b.Identifier) []interface{} {
-	var out = make([]map[string]interface{}, len(identifiers), len(identifiers))
+	var out = make([]interface{}, len(identifiers), len(identifiers))
 	for i, v := range identifiers {
 		m := make(map[string]interface{})
 		m["id"] = *v.Id
@@ -36,8 +37,23 @@ func flattenIdentifiers(identifiers []*muleb2b.Identifier) []interface{} {
 		m["status"] = *v.Status
 		out[i] = m
 	}
-	return []interface{}{out}
+	return out
 }
+
+// filterManagedIdentifiers returns the identifiers that have the same type and value as one of the managed identifiers
+func filterManagedIdentifiers(identifiers, managed []*muleb2b.Identifier) []*muleb2b.Identifier {
+	var out []*muleb2b.Identifier
+	for _, identifier := range identifiers {
+		for _, m := range managed {
+			if identifier.QualifierIdAndValueEqual(m) {
+				out = append(out, identifier)
+				break
+			}
+		}
+	}
+	return out
+}
+
 func expandIdentifiers(d interface{}) []*muleb2b.Identifier {
 	var identifiers []*muleb2b.Identifier
 	if d != nil {
@@ -89,7 +105,7 @@ func readContactConfig(data interface{}) ([]*muleb2b.Contact, error) {
 	return contacts, nil
 }
 func flattenContacts(contacts []*muleb2b.Contact) []interface{} {
-	var out = make([]map[string]interface{}, len(contacts), len(contacts))
+	var out = make([]interface{}, len(contacts), len(contacts))
 	for i, v := range contacts {
 		m := make(map[string]interface{})
 		m["id"] = *v.Id
@@ -109,7 +125,7 @@ func flattenContacts(contacts []*muleb2b.Contact) []interface{} {
 		}
 		out[i] = m
 	}
-	return []interface{}{out}
+	return out
 }
 func expandContacts(d interface{}) []*muleb2b.Contact {
 	var contacts []*muleb2b.Contact
@@ -211,3 +227,113 @@ func expandAddress(d interface{}) *muleb2b.Address {
 	}
 	return nil
 }
+
+// findPartnerAddress returns the partner's address, or nil if the partner does not have one.
+func findPartnerAddress(client *muleb2b.Client, partnerId string) (*muleb2b.Address, error) {
+	profile, err := client.GetPartnerProfile(partnerId)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || len(profile.Addresses) == 0 || profile.Addresses[0].Empty() {
+		return nil, nil
+	}
+	return profile.Addresses[0], nil
+}
+
+// describeIdentifier returns the qualifier and value of an identifier, or its ID when they are not set
+func describeIdentifier(identifier *muleb2b.Identifier) string {
+	if identifier.IdentifierTypeQualifierId != nil && identifier.Value != nil {
+		return *identifier.IdentifierTypeQualifierId + ", " + *identifier.Value
+	} else if identifier.Id != nil {
+		return *identifier.Id
+	}
+	return ""
+}
+
+// partnerIdentifiers holds a partner of an environment with its identifiers
+type partnerIdentifiers struct {
+	id          string
+	name        string
+	identifiers []*muleb2b.Identifier
+}
+
+// partnerIdentifiersCache holds the partners of each environment with their identifiers. Listing them takes a call per
+// partner, so the uniqueness check lists them once per provider run instead of once per planned resource
+type partnerIdentifiersCache struct {
+	lock         sync.Mutex
+	environments map[string][]partnerIdentifiers
+}
+
+// list returns the partners of the environment with their identifiers, listing them on the first call
+func (c *partnerIdentifiersCache) list(client *muleb2b.Client, envId string) ([]partnerIdentifiers, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if partners, ok := c.environments[envId]; ok {
+		return partners, nil
+	}
+
+	client.SetEnvironment(envId)
+
+	summaries, err := client.ListPartners()
+	if err != nil {
+		return nil, err
+	}
+
+	var partners []partnerIdentifiers
+	if summaries != nil {
+		for _, summary := range *summaries {
+			if summary.Id == nil {
+				continue
+			}
+
+			identifiers, err := client.ListPartnerIdentifiers(*summary.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			partner := partnerIdentifiers{id: *summary.Id, name: *summary.Id, identifiers: identifiers}
+			if summary.Name != nil {
+				partner.name = *summary.Name
+			}
+			partners = append(partners, partner)
+		}
+	}
+
+	if c.environments == nil {
+		c.environments = make(map[string][]partnerIdentifiers)
+	}
+	c.environments[envId] = partners
+	return partners, nil
+}
+
+// validateIdentifiersUnique returns an error naming the partner that already uses one of the identifiers
+// in the environment. Identifiers of the partners with the excluded IDs are not considered conflicts.
+func validateIdentifiersUnique(meta *providerMeta, envId string, identifiers []*muleb2b.Identifier, excludedPartnerIds ...string) error {
+	if len(identifiers) == 0 {
+		return nil
+	}
+
+	partners, err := meta.partnerIdentifiers.list(meta.client, envId)
+	if err != nil {
+		return err
+	}
+
+partners:
+	for _, partner := range partners {
+		for _, excluded := range excludedPartnerIds {
+			if partner.id == excluded {
+				continue partners
+			}
+		}
+
+		for _, identifier := range identifiers {
+			for _, e := range partner.identifiers {
+				if identifier.QualifierIdAndValueEqual(e) {
+					return fmt.Errorf("identifier (%s) is already used by partner (%s)", describeIdentifier(identifier), partner.name)
+				}
+			}
+		}
+	}
+	return nil
+}
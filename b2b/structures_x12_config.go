@@ -335,3 +335,20 @@ func getDefaultInboundTemplate() *muleb2b.X12 {
 
 	return &x12
 }
+
+// findPartnerInboundX12Configuration returns the partner's inbound X12 configuration, or nil if
+// the partner does not have one. Partners created outside of Terraform may not have one.
+func findPartnerInboundX12Configuration(client *muleb2b.Client, partnerId string) (*muleb2b.X12, error) {
+	x12s, err := client.ListPartnerX12Configurations(partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, x12 := range x12s {
+		if x12.FormatType != nil && *x12.FormatType == "X12InboundConfig" {
+			return &x12, nil
+		}
+	}
+
+	return nil, nil
+}
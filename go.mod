@@ -5,4 +5,5 @@ go 1.13
 require (
 	github.com/avioconsulting/muleb2b-api-go v0.0.0-20200330155028-d83a32fc8d57
 	github.com/hashicorp/terraform v0.12.24
+	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
 )